@@ -83,7 +83,9 @@ func doAdd(cmd *Command, args []string) (err error) {
 		if b, ok := builds[path]; ok {
 			return b
 		}
-		b := load.Instances([]string{path}, nil)[0]
+		b := load.Instances([]string{path}, &load.Config{
+			Warn: warner(cmd),
+		})[0]
 		builds[path] = b
 		return b
 	}
@@ -81,6 +81,36 @@ func exitOnErr(cmd *Command, err error, fatal bool) {
 		return
 	}
 
+	b := formatErr(err)
+	_, _ = cmd.Stderr().Write(b)
+	if fatal {
+		exit()
+	}
+}
+
+// printWarnings prints the warnings for v if the verbose flag is set.
+// Unlike errors, warnings do not result in a non-zero exit code.
+func printWarnings(cmd *Command, v cue.Value) {
+	if !flagVerbose.Bool(cmd) {
+		return
+	}
+	warn := warner(cmd)
+	for _, err := range errors.Errors(v.Warnings()) {
+		warn(err)
+	}
+}
+
+// warner returns a function for reporting warnings, such as those of the
+// loader, on the standard error of cmd.
+func warner(cmd *Command) func(err errors.Error) {
+	return func(err errors.Error) {
+		w := cmd.WarnStderr()
+		_, _ = io.WriteString(w, "warning: ")
+		_, _ = w.Write(formatErr(err))
+	}
+}
+
+func formatErr(err error) []byte {
 	// Link x/text as our localizer.
 	p := message.NewPrinter(getLang())
 	format := func(w io.Writer, format string, args ...interface{}) {
@@ -95,15 +125,14 @@ func exitOnErr(cmd *Command, err error, fatal bool) {
 		Cwd:     cwd,
 		ToSlash: inTest,
 	})
-
-	b := w.Bytes()
-	_, _ = cmd.Stderr().Write(b)
-	if fatal {
-		exit()
-	}
+	return w.Bytes()
 }
 
 func loadFromArgs(cmd *Command, args []string, cfg *load.Config) []*build.Instance {
+	if cfg == nil {
+		cfg = &load.Config{}
+	}
+	cfg.Warn = warner(cmd)
 	binst := load.Instances(args, cfg)
 	if len(binst) == 0 {
 		return nil
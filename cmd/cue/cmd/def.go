@@ -15,10 +15,24 @@
 package cmd
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
 	"github.com/spf13/cobra"
 
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/ast/astutil"
+	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/format"
+	"cuelang.org/go/cue/token"
+	"cuelang.org/go/internal"
+	"cuelang.org/go/internal/core/debug"
 	"cuelang.org/go/internal/encoding"
 	"cuelang.org/go/internal/filetypes"
+	"cuelang.org/go/internal/value"
 )
 
 // newDefCmd creates a new eval command
@@ -31,6 +45,12 @@ func newDefCmd(c *Command) *cobra.Command {
 Printing is skipped if validation fails.
 
 The --expression flag is used to only print parts of a configuration.
+
+The --split flag writes the output to the given directory instead, using
+one file per top-level definition. A definition #Foo is written to Foo.cue.
+All other declarations, including hidden definitions, are written to a file
+named after the package. Each file gets the package clause and the imports
+it needs, so that loading the directory yields the same configuration.
 `,
 		RunE: mkRunE(c, runDef),
 	}
@@ -47,6 +67,14 @@ The --expression flag is used to only print parts of a configuration.
 	cmd.Flags().Bool(string(flagInlineImports), false,
 		"expand references to non-core imports")
 
+	cmd.Flags().String(string(flagSplit), "",
+		"write each top-level definition to a separate file in this directory")
+
+	// For debugging the evaluator only.
+	cmd.Flags().Bool(string(flagDot), false,
+		"print the evaluated vertex graph in Graphviz DOT format")
+	_ = cmd.Flags().MarkHidden(string(flagDot))
+
 	// TODO: Option to include comments in output.
 	return cmd
 }
@@ -55,6 +83,18 @@ func runDef(cmd *Command, args []string) error {
 	b, err := parseArgs(cmd, args, &config{outMode: filetypes.Def})
 	exitOnErr(cmd, err, true)
 
+	if flagDot.Bool(cmd) {
+		err := writeDot(cmd, b)
+		exitOnErr(cmd, err, true)
+		return nil
+	}
+
+	if dir := flagSplit.String(cmd); dir != "" {
+		err := splitDefs(cmd, b, dir)
+		exitOnErr(cmd, err, true)
+		return nil
+	}
+
 	e, err := encoding.NewEncoder(b.outFile, b.encConfig)
 	exitOnErr(cmd, err, true)
 
@@ -76,3 +116,155 @@ func runDef(cmd *Command, args []string) error {
 
 	return nil
 }
+
+// writeDot writes the vertex graph of each value of b to stdout in DOT format.
+func writeDot(cmd *Command, b *buildPlan) error {
+	iter := b.instances()
+	defer iter.close()
+	for iter.scan() {
+		r, v := value.ToInternal(iter.value())
+		debug.WriteDot(cmd.OutOrStdout(), r, v, &debug.DotConfig{
+			HighlightErrors: true,
+		})
+	}
+	return iter.err()
+}
+
+// splitDefs writes the definitions of the configuration of b to separate
+// files in dir.
+func splitDefs(cmd *Command, b *buildPlan, dir string) error {
+	syn := []cue.Option{
+		cue.Docs(true),
+		cue.Attributes(true),
+		cue.Optional(true),
+		cue.Definitions(true),
+		cue.ResolveReferences(false),
+		cue.InlineImports(flagInlineImports.Bool(cmd)),
+	}
+
+	iter := b.instances()
+	defer iter.close()
+
+	var f *ast.File
+	for iter.scan() {
+		if f != nil {
+			return errors.Newf(token.NoPos,
+				"--split only supports a single instance")
+		}
+		if f = iter.file(); f == nil {
+			f = internal.ToFile(iter.value().Syntax(syn...))
+		}
+	}
+	if err := iter.err(); err != nil {
+		return err
+	}
+	if f == nil {
+		return nil
+	}
+
+	pkgName := f.PackageName()
+	if pkgName == "" {
+		pkgName = flagPackage.String(cmd)
+	}
+	if pkgName == "" || pkgName == "_" {
+		return errors.Newf(token.NoPos,
+			"--split requires a package name; use --package to set one")
+	}
+
+	var imports []*ast.ImportSpec
+	f.VisitImports(func(d *ast.ImportDecl) {
+		imports = append(imports, d.Specs...)
+	})
+
+	// newFile creates a file with a package clause and all imports. Unused
+	// imports are removed by sanitizing the file once it is complete.
+	newFile := func(pkg *ast.Package) *ast.File {
+		if pkg == nil {
+			pkg = &ast.Package{Name: ast.NewIdent(pkgName)}
+		}
+		nf := &ast.File{Decls: []ast.Decl{pkg}}
+		if len(imports) > 0 {
+			specs := append([]*ast.ImportSpec(nil), imports...)
+			nf.Decls = append(nf.Decls, &ast.ImportDecl{Specs: specs})
+		}
+		return nf
+	}
+
+	var pkg *ast.Package
+	for _, d := range f.Preamble() {
+		if p, ok := d.(*ast.Package); ok {
+			pkg = p
+		}
+	}
+
+	type splitFile struct {
+		name string
+		file *ast.File
+	}
+	var files []splitFile
+	rest := newFile(pkg)
+	for _, d := range f.Decls[len(f.Preamble()):] {
+		field, ok := d.(*ast.Field)
+		if !ok {
+			rest.Decls = append(rest.Decls, d)
+			continue
+		}
+		name, _, _ := ast.LabelName(field.Label)
+		if !internal.IsDef(name) || internal.IsHidden(name) {
+			rest.Decls = append(rest.Decls, d)
+			continue
+		}
+		df := newFile(nil)
+		df.Decls = append(df.Decls, field)
+		files = append(files, splitFile{strings.TrimPrefix(name, "#"), df})
+	}
+	if len(rest.Decls) > len(rest.Preamble()) {
+		files = append(files, splitFile{pkgName, rest})
+	}
+
+	seen := map[string]bool{}
+	for _, sf := range files {
+		if seen[sf.name] {
+			return errors.Newf(token.NoPos,
+				"--split: multiple declarations map to file %s.cue", sf.name)
+		}
+		seen[sf.name] = true
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, sf := range files {
+		if err := astutil.Sanitize(sf.file); err != nil {
+			return err
+		}
+		// Drop import declarations of which all imports were unused.
+		decls := sf.file.Decls[:0]
+		for _, d := range sf.file.Decls {
+			if x, ok := d.(*ast.ImportDecl); ok && len(x.Specs) == 0 {
+				continue
+			}
+			decls = append(decls, d)
+		}
+		sf.file.Decls = decls
+		if p := len(sf.file.Preamble()); p < len(decls) {
+			ast.SetRelPos(decls[p], token.NewSection)
+		}
+
+		b, err := format.Node(sf.file, format.Simplify())
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(dir, sf.name+".cue")
+		if !flagForce.Bool(cmd) {
+			if _, err := os.Stat(path); err == nil {
+				return errors.Wrapf(os.ErrExist, token.NoPos,
+					"error writing %q", path)
+			}
+		}
+		if err := ioutil.WriteFile(path, b, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
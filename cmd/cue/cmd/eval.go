@@ -118,6 +118,7 @@ func runEval(cmd *Command, args []string) error {
 			id = iter.id()
 		}
 		v := iter.value()
+		printWarnings(cmd, v)
 
 		errHeader := func() {
 			if id != "" {
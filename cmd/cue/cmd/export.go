@@ -15,10 +15,20 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
 	"github.com/spf13/cobra"
 
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/token"
 	"cuelang.org/go/internal/encoding"
 	"cuelang.org/go/internal/filetypes"
+	"cuelang.org/go/internal/value"
 )
 
 // newExportCmd creates and export command
@@ -87,6 +97,54 @@ text    output as raw text
 
 yaml    output as YAML
                 Outputs any CUE value.
+
+
+Wrapping
+The --wrap flag nests each exported value under the given key. Dotted keys
+result in nested structs. For instance, with --wrap=spec.config the value
+{a: 1} is exported as
+
+	{
+		"spec": {
+			"config": {
+				"a": 1
+			}
+		}
+	}
+
+For streaming output formats, such as jsonl, each emitted value is wrapped
+individually.
+
+
+Positions
+The --positions flag emits, after each exported value, a separate document
+that maps each value in the output to the position in the CUE sources from
+which it was ultimately derived. Values are identified by JSON Pointer
+(RFC 6901), where the empty pointer denotes the exported value itself.
+For instance,
+
+	// config.cue
+	a: 1
+	b: [a]
+
+yields
+
+	{
+		"a": 1,
+		"b": [
+			1
+		]
+	}
+	{
+		"$positions": {
+			"": "./config.cue:2:1",
+			"/a": "./config.cue:2:4",
+			"/b": "./config.cue:3:4",
+			"/b/0": "./config.cue:2:4"
+		}
+	}
+
+Values for which no position is known are omitted.
 `,
 
 		RunE: mkRunE(c, runExport),
@@ -98,6 +156,8 @@ yaml    output as YAML
 
 	cmd.Flags().Bool(string(flagEscape), false, "use HTML escaping")
 	cmd.Flags().StringArrayP(string(flagExpression), "e", nil, "export this expression only")
+	cmd.Flags().String(string(flagWrap), "", "nest the exported value under this, possibly dotted, key")
+	cmd.Flags().Bool(string(flagPositions), false, "emit the source position of each exported value in a separate document")
 
 	return cmd
 }
@@ -106,6 +166,9 @@ func runExport(cmd *Command, args []string) error {
 	b, err := parseArgs(cmd, args, &config{outMode: filetypes.Export})
 	exitOnErr(cmd, err, true)
 
+	wrap, err := parseWrapKey(flagWrap.String(cmd))
+	exitOnErr(cmd, err, true)
+
 	enc, err := encoding.NewEncoder(b.outFile, b.encConfig)
 	exitOnErr(cmd, err, true)
 	defer enc.Close()
@@ -114,9 +177,106 @@ func runExport(cmd *Command, args []string) error {
 	defer iter.close()
 	for iter.scan() {
 		v := iter.value()
+		if len(wrap.Selectors()) > 0 {
+			v = v.Context().CompileString("{}").FillPath(wrap, v)
+		}
 		err = enc.Encode(v)
 		exitOnErr(cmd, err, true)
+		if flagPositions.Bool(cmd) {
+			err = enc.Encode(positions(v))
+			exitOnErr(cmd, err, true)
+		}
 	}
 	exitOnErr(cmd, iter.err(), true)
 	return nil
 }
+
+// parseWrapKey parses the argument of the --wrap flag. The key must consist
+// of regular field labels only.
+func parseWrapKey(key string) (cue.Path, error) {
+	if key == "" {
+		return cue.Path{}, nil
+	}
+	p := cue.ParsePath(key)
+	if err := p.Err(); err != nil {
+		return cue.Path{}, fmt.Errorf("invalid --%s key %q: %v", flagWrap, key, err)
+	}
+	for _, sel := range p.Selectors() {
+		if sel.Type() != cue.StringLabel {
+			return cue.Path{}, fmt.Errorf("invalid --%s key %q: %s is not a regular field",
+				flagWrap, key, sel)
+		}
+	}
+	return p, nil
+}
+
+// positions returns a value with a single field, $positions, that maps the
+// JSON Pointer of v and each of the values it contains to their source
+// position.
+func positions(v cue.Value) cue.Value {
+	cwd, _ := os.Getwd()
+	m := &ast.StructLit{}
+	var walk func(ptr string, v cue.Value)
+	walk = func(ptr string, v cue.Value) {
+		if p := finalPos(v); p.IsValid() {
+			m.Elts = append(m.Elts, &ast.Field{
+				Label: ast.NewString(ptr),
+				Value: ast.NewString(positionString(cwd, p)),
+			})
+		}
+		switch v.Kind() {
+		case cue.StructKind:
+			iter, _ := v.Fields()
+			for iter.Next() {
+				walk(ptr+"/"+escapePointer(iter.Label()), iter.Value())
+			}
+		case cue.ListKind:
+			iter, _ := v.List()
+			for i := 0; iter.Next(); i++ {
+				walk(ptr+"/"+strconv.Itoa(i), iter.Value())
+			}
+		}
+	}
+	walk("", v)
+	return v.Context().BuildExpr(ast.NewStruct("$positions", m))
+}
+
+// finalPos reports the position of the expression from which the value of v
+// was ultimately derived, such as the literal at the end of a chain of
+// references. It falls back to the position of v for values, like structs and
+// lists, that are composed from multiple sources.
+func finalPos(v cue.Value) token.Pos {
+	v, _ = v.Default()
+	if _, x := value.ToInternal(v); x != nil {
+		if src := x.Value().Source(); src != nil && src.Pos().IsValid() {
+			return src.Pos()
+		}
+	}
+	if f, ok := v.Source().(*ast.Field); ok {
+		return f.Value.Pos()
+	}
+	return v.Pos()
+}
+
+// positionString formats p as it would appear in an error message.
+func positionString(cwd string, p token.Pos) string {
+	pos := p.Position()
+	s := pos.Filename
+	if rel, err := filepath.Rel(cwd, s); err == nil && cwd != "" {
+		s = rel
+		if !strings.HasPrefix(s, ".") {
+			s = "." + string(filepath.Separator) + s
+		}
+	}
+	if inTest {
+		s = filepath.ToSlash(s)
+	}
+	return fmt.Sprintf("%s:%d:%d", s, pos.Line, pos.Column)
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// escapePointer escapes a field label for use as a JSON Pointer token.
+func escapePointer(label string) string {
+	return pointerEscaper.Replace(label)
+}
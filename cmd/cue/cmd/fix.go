@@ -85,6 +85,7 @@ func runFixAll(cmd *Command, args []string) error {
 	instances := load.Instances(args, &load.Config{
 		Tests: true,
 		Tools: true,
+		Warn:  warner(cmd),
 	})
 
 	errs := fix.Instances(instances, opts...)
@@ -50,6 +50,13 @@ const (
 	flagWithContext flagName = "with-context"
 	flagOut         flagName = "out"
 	flagOutFile     flagName = "outfile"
+	flagSplit       flagName = "split"
+	flagWrap        flagName = "wrap"
+	flagPositions   flagName = "positions"
+	flagDot         flagName = "dot"
+	flagOpenStructs flagName = "open-structs"
+
+	flagLanguageVersion flagName = "language-version"
 )
 
 func addOutFlags(f *pflag.FlagSet, allowNonCUE bool) {
@@ -23,6 +23,9 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/mod/semver"
+
+	"cuelang.org/go/internal"
 )
 
 func newModCmd(c *Command) *cobra.Command {
@@ -61,11 +64,17 @@ to the new subdirectory.
 A module name is optional, but if it is not given a packages
 within the module cannot imported another package defined
 in the module.
+
+The module file records the version of the CUE language
+supported by this cue command in the language.version field.
+Use --language-version to record a different version.
 `,
 		RunE: mkRunE(c, runModInit),
 	}
 
 	cmd.Flags().BoolP(string(flagForce), "f", false, "force moving old-style cue.mod file")
+	cmd.Flags().String(string(flagLanguageVersion), internal.LanguageVersion(),
+		"CUE language version recorded in the module file")
 
 	return cmd
 }
@@ -94,6 +103,11 @@ func runModInit(cmd *Command, args []string) (err error) {
 		}
 	}
 
+	langVersion := flagLanguageVersion.String(cmd)
+	if !semver.IsValid(langVersion) {
+		return fmt.Errorf("invalid language version %q", langVersion)
+	}
+
 	cwd, err := os.Getwd()
 	if err != nil {
 		return err
@@ -129,6 +143,13 @@ func runModInit(cmd *Command, args []string) (err error) {
 
 	// Set module even if it is empty, making it easier for users to fill it in.
 	_, err = fmt.Fprintf(f, "module: %q\n", module)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(f, "language: version: %q\n", langVersion)
+	if err != nil {
+		return err
+	}
 
 	if err = os.Mkdir(filepath.Join(mod, "usr"), 0755); err != nil {
 		return err
@@ -214,6 +214,12 @@ func (c *Command) Stderr() io.Writer {
 	return (*errWriter)(c)
 }
 
+// WarnStderr returns a writer that should be used for warnings. Unlike
+// messages written to Stderr, warnings do not result in a non-zero exit code.
+func (c *Command) WarnStderr() io.Writer {
+	return c.Command.OutOrStderr()
+}
+
 // TODO: add something similar for Stdout. The output model of Cobra isn't
 // entirely clear, and such a change seems non-trivial.
 
@@ -127,6 +127,7 @@ func runTrim(cmd *Command, args []string) error {
 
 	cfg := *defaultConfig.loadCfg
 	cfg.Overlay = overlay
+	cfg.Warn = warner(cmd)
 	tinsts := buildInstances(cmd, load.Instances(args, &cfg), false)
 	if len(tinsts) != len(binst) {
 		return errors.New("unexpected number of new instances")
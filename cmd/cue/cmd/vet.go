@@ -20,6 +20,8 @@ import (
 
 	"cuelang.org/go/cue"
 	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/internal/core/adt"
+	"cuelang.org/go/internal/value"
 )
 
 const vetDoc = `vet validates CUE and other data files
@@ -59,6 +61,20 @@ Examples:
   cue vet translations/*.yaml foo.cue -d '#Translation'
 
 If more than one expression is given, all must match all values.
+
+
+Checking for open structs
+
+The --open-structs flag additionally reports structs in the schema that are
+open, meaning they allow arbitrary fields, so that misspelled field names are
+not caught. Any struct that is not concrete is considered to be part of the
+schema. Structs that are explicitly marked as open with ... and structs with
+pattern constraints, such as [string]: int, are accepted. Structs defined
+within a definition are closed by default. This check only applies when
+vetting CUE packages, not when checking data files.
+
+  # Report open structs in the schema of the current package:
+  cue vet --open-structs
 `
 
 func newVetCmd(c *Command) *cobra.Command {
@@ -74,6 +90,8 @@ func newVetCmd(c *Command) *cobra.Command {
 
 	cmd.Flags().BoolP(string(flagConcrete), "c", false,
 		"require the evaluation to be concrete")
+	cmd.Flags().Bool(string(flagOpenStructs), false,
+		"report open structs in the schema")
 
 	return cmd
 }
@@ -121,6 +139,8 @@ func doVet(cmd *Command, args []string) error {
 			cue.Definitions(true),
 			cue.Hidden(true),
 		}
+		printWarnings(cmd, v)
+
 		w := cmd.Stderr()
 		err := v.Validate(append(opt, cue.Concrete(concrete))...)
 		if err != nil && !hasFlag {
@@ -133,11 +153,64 @@ func doVet(cmd *Command, args []string) error {
 			}
 		}
 		exitOnErr(cmd, err, false)
+
+		if flagOpenStructs.Bool(cmd) {
+			exitOnErr(cmd, checkOpenStructs(v), false)
+		}
 	}
 	exitOnErr(cmd, iter.err(), true)
 	return nil
 }
 
+// checkOpenStructs reports an error for each non-concrete struct in v that
+// allows arbitrary fields without being explicitly marked as open.
+func checkOpenStructs(v cue.Value) (errs errors.Error) {
+	var walk func(v cue.Value)
+	walk = func(v cue.Value) {
+		if v.Validate(cue.Concrete(true)) == nil {
+			return // data, not schema
+		}
+		switch v.IncompleteKind() {
+		case cue.StructKind:
+			if isOpenStruct(v) {
+				errs = errors.Append(errs, errors.Newf(v.Pos(),
+					"%v: open struct; close it or add ... to mark it as open", v.Path()))
+			}
+			iter, _ := v.Fields(cue.Definitions(true), cue.Optional(true))
+			for iter.Next() {
+				walk(iter.Value())
+			}
+
+		case cue.ListKind:
+			iter, _ := v.List()
+			for iter.Next() {
+				walk(iter.Value())
+			}
+		}
+	}
+	// The top-level struct of a package is open by nature.
+	iter, _ := v.Fields(cue.Definitions(true), cue.Optional(true))
+	for iter.Next() {
+		walk(iter.Value())
+	}
+	return errs
+}
+
+// isOpenStruct reports whether v is a struct that is not closed and does not
+// declare its openness with an ellipsis or pattern constraint.
+func isOpenStruct(v cue.Value) bool {
+	_, x := value.ToInternal(v)
+	if _, ok := x.BaseValue.(*adt.StructMarker); !ok || x.IsClosedStruct() {
+		return false
+	}
+	for _, s := range x.Structs {
+		if s.IsOpen || len(s.Bulk) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
 func vetFiles(cmd *Command, b *buildPlan) {
 	// Use -r type root, instead of -e
 
@@ -149,6 +222,7 @@ func vetFiles(cmd *Command, b *buildPlan) {
 	defer iter.close()
 	for iter.scan() {
 		v := iter.value()
+		printWarnings(cmd, v)
 
 		// Always concrete when checking against concrete files.
 		err := v.Validate(cue.Concrete(true))
@@ -70,6 +70,8 @@ type Instance struct {
 
 	parent *Instance // TODO: for cycle detection
 
+	meta map[interface{}]interface{}
+
 	// The following fields are for informative purposes and are not used by
 	// the cue package to create an instance.
 
@@ -177,6 +179,22 @@ func (inst *Instance) ReportError(err errors.Error) {
 	inst.Err = errors.Append(inst.Err, err)
 }
 
+// SetMeta associates value with key for inst. The association is retained
+// by instances built from inst. As with context.WithValue, key must be
+// comparable and should be of an unexported type to avoid collisions.
+func (inst *Instance) SetMeta(key, value interface{}) {
+	if inst.meta == nil {
+		inst.meta = map[interface{}]interface{}{}
+	}
+	inst.meta[key] = value
+}
+
+// Meta returns the value associated with key by SetMeta, or nil if there is
+// no such value.
+func (inst *Instance) Meta(key interface{}) interface{} {
+	return inst.meta[key]
+}
+
 // Context defines the build context for this instance. All files defined
 // in Syntax as well as all imported instances must be created using the
 // same build context.
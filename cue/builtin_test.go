@@ -109,7 +109,7 @@ func TestBuiltins(t *testing.T) {
 		test("struct", `struct.MinFields(2) & {a: 1}`),
 		// TODO: original value may be better.
 		// `_|_ // invalid value {a:1} (does not satisfy struct.MinFields(2))`,
-		`_|_ // invalid value {a:1} (does not satisfy struct.MinFields(2)): len(fields) < MinFields(2) (1 < 2)`,
+		`_|_ // invalid value {a:1} (does not satisfy struct.MinFields(2)): struct must have at least 2 fields, got 1`,
 	}, {
 		test("time", `time.Time & "1937-01-01T12:00:27.87+00:20"`),
 		`"1937-01-01T12:00:27.87+00:20"`,
@@ -0,0 +1,154 @@
+// Copyright 2022 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cue
+
+import (
+	"cuelang.org/go/cue/errors"
+)
+
+// A CompatLevel describes how two versions of a schema relate to each other
+// with respect to the data they accept.
+type CompatLevel int
+
+const (
+	// Incompatible indicates that neither version of the schema accepts all
+	// data accepted by the other.
+	Incompatible CompatLevel = 0
+
+	// BackwardCompatible indicates that the new version of a schema accepts
+	// all data accepted by the old version.
+	BackwardCompatible CompatLevel = 1 << iota
+
+	// ForwardCompatible indicates that the old version of a schema accepts
+	// all data accepted by the new version.
+	ForwardCompatible
+
+	// FullyCompatible indicates that both versions of a schema accept the
+	// same data.
+	FullyCompatible = BackwardCompatible | ForwardCompatible
+)
+
+func (c CompatLevel) String() string {
+	switch c {
+	case Incompatible:
+		return "incompatible"
+	case BackwardCompatible:
+		return "backward"
+	case ForwardCompatible:
+		return "forward"
+	case FullyCompatible:
+		return "full"
+	}
+	return "unknown"
+}
+
+// Compatibility reports the compatibility level between an old and a new
+// version of a schema. The new version is backward compatible if it subsumes
+// the old version, meaning that it accepts all data valid under the old
+// version, and forward compatible if the old version subsumes the new one.
+//
+// Unless the versions are fully compatible, the returned error lists the
+// changes breaking compatibility, such as removed fields, added required
+// fields, and narrowed or widened types, with the positions at which they
+// occur.
+func Compatibility(old, new Value) (CompatLevel, error) {
+	level := Incompatible
+	if new.Subsume(old) == nil {
+		level |= BackwardCompatible
+	}
+	if old.Subsume(new) == nil {
+		level |= ForwardCompatible
+	}
+	if level == FullyCompatible {
+		return level, nil
+	}
+
+	var c compatChecker
+	c.check(old, new)
+	if c.errs == nil {
+		// Catch-all for incompatibilities not attributed to a specific field.
+		c.errs = errors.Newf(new.Pos(), "schemas are not fully compatible")
+	}
+	return level, c.errs
+}
+
+type compatChecker struct {
+	errs errors.Error
+}
+
+func (c *compatChecker) addErr(v Value, format string, args ...interface{}) {
+	args = append([]interface{}{v.Path()}, args...)
+	c.errs = errors.Append(c.errs, errors.Newf(v.Pos(), "%v: "+format, args...))
+}
+
+func (c *compatChecker) check(old, new Value) {
+	if old.IncompleteKind() != StructKind || new.IncompleteKind() != StructKind {
+		backward := new.Subsume(old) == nil
+		forward := old.Subsume(new) == nil
+		switch {
+		case !backward && !forward:
+			c.addErr(new, "changed type from %v to %v", old, new)
+		case !backward:
+			c.addErr(new, "narrowed type from %v to %v", old, new)
+		case !forward:
+			c.addErr(new, "widened type from %v to %v", old, new)
+		}
+		return
+	}
+
+	opts := []Option{Definitions(true), Optional(true)}
+
+	iter, _ := old.Fields(opts...)
+	for iter.Next() {
+		sel := iter.Selector()
+		o := iter.Value()
+		n := new.LookupPath(MakePath(sel))
+		if !n.Exists() {
+			n = new.LookupPath(MakePath(sel.Optional()))
+		}
+		if !n.Exists() {
+			c.addErr(o, "field removed")
+			continue
+		}
+		if oldOpt, newOpt := iter.IsOptional(), isOptional(new, sel); oldOpt != newOpt {
+			if newOpt {
+				c.addErr(n, "field made optional")
+			} else {
+				c.addErr(n, "field made required")
+			}
+		}
+		c.check(o, n)
+	}
+
+	iter, _ = new.Fields(opts...)
+	for iter.Next() {
+		sel := iter.Selector()
+		if old.LookupPath(MakePath(sel)).Exists() ||
+			old.LookupPath(MakePath(sel.Optional())).Exists() {
+			continue
+		}
+		if iter.IsOptional() {
+			c.addErr(iter.Value(), "optional field added")
+		} else {
+			c.addErr(iter.Value(), "required field added")
+		}
+	}
+}
+
+// isOptional reports whether the field sel of v is optional.
+func isOptional(v Value, sel Selector) bool {
+	return !v.LookupPath(MakePath(sel)).Exists() &&
+		v.LookupPath(MakePath(sel.Optional())).Exists()
+}
@@ -15,6 +15,9 @@
 package cue
 
 import (
+	"sort"
+	"time"
+
 	"cuelang.org/go/cue/ast"
 	"cuelang.org/go/cue/ast/astutil"
 	"cuelang.org/go/cue/build"
@@ -105,6 +108,131 @@ func InferBuiltins(elide bool) BuildOption {
 	}
 }
 
+// Limits bounds the resources used for evaluating a value. A zero value for
+// any of the fields means the respective resource is not bounded.
+//
+// Limits are intended for evaluating untrusted input. See EvalLimits.
+type Limits struct {
+	// MaxIterations bounds the number of unification steps, which roughly
+	// corresponds to the number of evaluated values.
+	MaxIterations int
+
+	// MaxDepth bounds the nesting depth of evaluated values.
+	MaxDepth int
+
+	// MaxDisjuncts bounds the number of disjuncts that are processed.
+	MaxDisjuncts int
+
+	// Timeout bounds the duration of the evaluation.
+	Timeout time.Duration
+}
+
+// ErrLimitExceeded is matched, using errors.Is, by all errors that result from
+// exceeding Limits. Errors resulting from a timeout additionally match
+// context.DeadlineExceeded.
+var ErrLimitExceeded = adt.ErrLimitExceeded
+
+// EvalLimits causes the built value to be fully evaluated within the given
+// limits. If a limit is exceeded, evaluation is aborted and the resulting
+// Value is an error that cites the path being evaluated at that point.
+//
+// Evaluation involving the resulting Value later on, for instance through
+// Unify or FillPath, is not subject to these limits.
+func EvalLimits(l Limits) BuildOption {
+	return func(o *runtime.Config) {
+		o.Limits = &adt.Limits{
+			MaxIterations: l.MaxIterations,
+			MaxDepth:      l.MaxDepth,
+			MaxDisjuncts:  l.MaxDisjuncts,
+			Timeout:       l.Timeout,
+		}
+	}
+}
+
+// A Profile reports the cost of evaluating the individual values of a
+// configuration. See EvalProfile.
+//
+// This is an experimental type and its contents may change without notice.
+type Profile struct {
+	r *runtime.Runtime
+	p adt.Profile
+}
+
+// A ProfileEntry holds the evaluation cost recorded for a single path.
+type ProfileEntry struct {
+	Path Path
+
+	// Time is the time spent evaluating the value at Path, excluding the time
+	// spent evaluating its fields and elements, or other values to which it
+	// refers.
+	Time time.Duration
+
+	// Unifications is the number of times the value was unified. Unlike Time,
+	// it does not vary between runs.
+	Unifications int
+
+	// Disjuncts is the number of disjuncts processed for the value.
+	Disjuncts int
+}
+
+// Entries returns the recorded costs, ordered by decreasing Time. The costs of
+// evaluating the disjuncts of a value are included in those of the value.
+func (p *Profile) Entries() []ProfileEntry {
+	m := map[string]*ProfileEntry{}
+	var a []*ProfileEntry
+	for v, x := range p.p.Entries() {
+		var sels []Selector
+		for _, f := range v.Path() {
+			sels = append(sels, featureToSel(f, p.r))
+		}
+		path := MakePath(sels...)
+		e := m[path.String()]
+		if e == nil {
+			e = &ProfileEntry{Path: path}
+			m[path.String()] = e
+			a = append(a, e)
+		}
+		e.Time += x.Time
+		e.Unifications += x.Calls
+		e.Disjuncts += x.Disjuncts
+	}
+	sort.Slice(a, func(i, j int) bool {
+		if a[i].Time != a[j].Time {
+			return a[i].Time > a[j].Time
+		}
+		return a[i].Path.String() < a[j].Path.String()
+	})
+	entries := make([]ProfileEntry, len(a))
+	for i, e := range a {
+		entries[i] = *e
+	}
+	return entries
+}
+
+// EvalProfile causes the built value to be fully evaluated, recording the cost
+// of evaluating each of its values in p. This is intended for finding the
+// parts of a configuration that are expensive to evaluate.
+//
+// Evaluation involving the resulting Value later on, for instance through
+// Unify or FillPath, is not recorded.
+func EvalProfile(p *Profile) BuildOption {
+	return func(o *runtime.Config) {
+		p.r = o.Runtime
+		o.Profile = &p.p
+	}
+}
+
+// instrument sets up ctx for evaluating a value with the limits and profile of
+// cfg, if any. The returned function must be called once evaluation is done.
+func instrument(ctx *adt.OpContext, cfg *runtime.Config) (release func()) {
+	release = func() {}
+	if cfg.Limits != nil {
+		release = ctx.SetLimits(*cfg.Limits)
+	}
+	ctx.SetProfile(cfg.Profile)
+	return release
+}
+
 func (c *Context) parseOptions(options []BuildOption) (cfg runtime.Config) {
 	cfg.Runtime = (*runtime.Runtime)(c)
 	for _, f := range options {
@@ -123,7 +251,7 @@ func (c *Context) BuildInstance(i *build.Instance, options ...BuildOption) Value
 	if err != nil {
 		return c.makeError(err)
 	}
-	return c.make(v)
+	return c.makeLimited(&cfg, nil, v)
 }
 
 func (c *Context) makeError(err errors.Error) Value {
@@ -157,14 +285,15 @@ func (c *Context) BuildInstances(instances []*build.Instance) ([]Value, error) {
 // error occurred.
 func (c *Context) BuildFile(f *ast.File, options ...BuildOption) Value {
 	cfg := c.parseOptions(options)
-	return c.compile(c.runtime().CompileFile(&cfg, f))
+	v, p := c.runtime().CompileFile(&cfg, f)
+	return c.compile(&cfg, v, p)
 }
 
-func (c *Context) compile(v *adt.Vertex, p *build.Instance) Value {
+func (c *Context) compile(cfg *runtime.Config, v *adt.Vertex, p *build.Instance) Value {
 	if p.Err != nil {
 		return c.makeError(p.Err)
 	}
-	return c.make(v)
+	return c.makeLimited(cfg, nil, v)
 }
 
 // BuildExpr creates a Value from x.
@@ -176,6 +305,7 @@ func (c *Context) BuildExpr(x ast.Expr, options ...BuildOption) Value {
 	cfg := c.parseOptions(options)
 
 	ctx := c.ctx()
+	defer instrument(ctx, &cfg)()
 
 	// TODO: move to runtime?: it probably does not make sense to treat BuildExpr
 	// and the expression resulting from CompileString differently.
@@ -192,7 +322,7 @@ func (c *Context) BuildExpr(x ast.Expr, options ...BuildOption) Value {
 	}
 	v := adt.Resolve(ctx, conjunct)
 
-	return c.make(v)
+	return c.makeLimited(&cfg, ctx, v)
 }
 
 func errFn(pos token.Pos, msg string, args ...interface{}) {}
@@ -219,7 +349,8 @@ const anonymousPkg = "_"
 // error occurred.
 func (c *Context) CompileString(src string, options ...BuildOption) Value {
 	cfg := c.parseOptions(options)
-	return c.compile(c.runtime().Compile(&cfg, src))
+	v, p := c.runtime().Compile(&cfg, src)
+	return c.compile(&cfg, v, p)
 }
 
 // CompileBytes parses and build a Value from the given source bytes.
@@ -228,7 +359,8 @@ func (c *Context) CompileString(src string, options ...BuildOption) Value {
 // error occurred.
 func (c *Context) CompileBytes(b []byte, options ...BuildOption) Value {
 	cfg := c.parseOptions(options)
-	return c.compile(c.runtime().Compile(&cfg, b))
+	v, p := c.runtime().Compile(&cfg, b)
+	return c.compile(&cfg, v, p)
 }
 
 // TODO: fs.FS or custom wrapper?
@@ -251,6 +383,32 @@ func (c *Context) make(v *adt.Vertex) Value {
 	return x
 }
 
+// makeLimited is like make, but fully evaluates v within the limits of cfg, if
+// any, recording its costs in the profile of cfg, if any. If ctx is not nil,
+// it must have been set up with instrument already.
+func (c *Context) makeLimited(cfg *runtime.Config, ctx *adt.OpContext, v *adt.Vertex) Value {
+	if cfg.Limits == nil && cfg.Profile == nil {
+		return c.make(v)
+	}
+	if ctx == nil {
+		ctx = c.ctx()
+		defer instrument(ctx, cfg)()
+	}
+
+	// Evaluate a copy, as v may be cached by the runtime and should not
+	// retain errors that are specific to these limits.
+	w := &adt.Vertex{}
+	for _, x := range v.Conjuncts {
+		w.AddConjunct(x)
+	}
+	w.Finalize(ctx)
+
+	if err := ctx.LimitError(); err != nil {
+		return c.makeError(err.Err)
+	}
+	return c.make(w)
+}
+
 // An EncodeOption defines options for the various encoding-related methods of
 // Context.
 type EncodeOption func(*encodeOptions)
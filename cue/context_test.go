@@ -15,8 +15,13 @@
 package cue_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"cuelang.org/go/cue"
 	"cuelang.org/go/cue/build"
@@ -136,3 +141,142 @@ bar: [
 		t.Fatalf("BuildInstances() = %#v, wanted error", vs)
 	}
 }
+
+func TestEvalLimits(t *testing.T) {
+	ctx := cuecontext.New()
+
+	testCases := []struct {
+		desc   string
+		in     string
+		limits cue.Limits
+		err    string
+	}{{
+		desc:   "within limits",
+		in:     `a: b: c: *1 | 2`,
+		limits: cue.Limits{MaxIterations: 100, MaxDepth: 3, MaxDisjuncts: 100},
+	}, {
+		desc:   "iterations",
+		in:     `a: [for x in [1, 2, 3, 4, 5] {x}]`,
+		limits: cue.Limits{MaxIterations: 5},
+		err:    "a.2: too many iterations (limit 5): evaluation limit exceeded",
+	}, {
+		desc:   "depth",
+		in:     `a: b: c: d: 1`,
+		limits: cue.Limits{MaxDepth: 3},
+		err:    "a.b.c.d: value nested too deeply (limit 3): evaluation limit exceeded",
+	}, {
+		desc:   "disjuncts",
+		in:     `a: (1 | 2 | 3) & (1 | 2 | 3) & (1 | 2 | 3)`,
+		limits: cue.Limits{MaxDisjuncts: 10},
+		err:    "a: too many disjuncts (limit 10): evaluation limit exceeded",
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			v := ctx.CompileString(tc.in, cue.EvalLimits(tc.limits))
+			err := v.Err()
+			if tc.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.err {
+				t.Fatalf(" got: %v\nwant: %v", err, tc.err)
+			}
+			if !errors.Is(err, cue.ErrLimitExceeded) {
+				t.Errorf("error does not match ErrLimitExceeded")
+			}
+
+			// The limits only apply to the evaluation with EvalLimits.
+			if err := ctx.CompileString(tc.in).Err(); err != nil {
+				t.Errorf("unexpected error without limits: %v", err)
+			}
+		})
+	}
+
+	t.Run("timeout", func(t *testing.T) {
+		l := "[" + strings.Repeat("0, ", 100) + "]"
+		v := ctx.CompileString(fmt.Sprintf(`
+			l: %s
+			x: [for a in l for b in l for c in l {a + b + c}]
+			`, l), cue.EvalLimits(cue.Limits{Timeout: time.Millisecond}))
+		err := v.Err()
+		if err == nil || !strings.HasPrefix(err.Error(), "x: timed out after 1ms") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !errors.Is(err, cue.ErrLimitExceeded) {
+			t.Errorf("error does not match ErrLimitExceeded")
+		}
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("error does not match context.DeadlineExceeded")
+		}
+	})
+}
+
+func TestEvalProfile(t *testing.T) {
+	ctx := cuecontext.New()
+
+	var p cue.Profile
+	v := ctx.CompileString(`
+		a: (1 | 2 | 3) & (1 | 2 | 3)
+		b: c: [1, 2]
+		`, cue.EvalProfile(&p))
+	if err := v.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := p.Entries()
+	got := map[string]cue.ProfileEntry{}
+	for i, e := range entries {
+		got[e.Path.String()] = e
+		if i > 0 && e.Time > entries[i-1].Time {
+			t.Errorf("entries not sorted by time: %v before %v", entries[i-1], e)
+		}
+	}
+	for _, path := range []string{"", "a", "b", "b.c", "b.c[0]", "b.c[1]"} {
+		e, ok := got[path]
+		if !ok {
+			t.Errorf("no entry for path %q", path)
+			continue
+		}
+		if e.Unifications == 0 {
+			t.Errorf("%q: no unifications recorded", path)
+		}
+	}
+	if got["a"].Disjuncts <= got["b"].Disjuncts {
+		t.Errorf("a: got %d disjuncts; want more than %d", got["a"].Disjuncts, got["b"].Disjuncts)
+	}
+
+	// Values built without EvalProfile are not recorded.
+	n := len(entries)
+	ctx.CompileString(`a: 1`)
+	if got := len(p.Entries()); got != n {
+		t.Errorf("got %d entries after unprofiled evaluation; want %d", got, n)
+	}
+}
+
+// BenchmarkCompileRetained reports the heap memory retained by a compiled
+// value of a large configuration in which the same field labels are used many
+// times.
+func BenchmarkCompileRetained(b *testing.B) {
+	var buf strings.Builder
+	for i := 0; i < 10000; i++ {
+		fmt.Fprintf(&buf, "f%d: {name: \"n\", port: 1, enabled: true, replicas: 2}\n", i)
+	}
+	src := buf.String()
+	var retained uint64
+	for i := 0; i < b.N; i++ {
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		v := cuecontext.New().CompileString(src)
+		if err := v.Validate(); err != nil {
+			b.Fatal(err)
+		}
+		runtime.GC()
+		runtime.ReadMemStats(&after)
+		runtime.KeepAlive(v)
+		retained += after.HeapAlloc - before.HeapAlloc
+	}
+	b.ReportMetric(float64(retained)/float64(b.N), "retained-B/op")
+}
@@ -15,8 +15,11 @@
 package cuecontext
 
 import (
+	"fmt"
+
 	"cuelang.org/go/cue"
 	"cuelang.org/go/internal/core/runtime"
+	"cuelang.org/go/internal/value"
 
 	_ "cuelang.org/go/pkg"
 )
@@ -27,5 +30,51 @@ type Option interface{ buildOption() }
 // New creates a new Context.
 func New(options ...Option) *cue.Context {
 	r := runtime.New()
+	for _, o := range options {
+		switch x := o.(type) {
+		case importResolver:
+			r.SetImportResolver(x.runtimeResolver(r))
+		}
+	}
 	return (*cue.Context)(r)
 }
+
+// ImportResolver returns an Option that sets f as the function to resolve
+// import paths that are neither builtin nor part of the instance being built,
+// for instance to fetch schemas from a registry at runtime.
+//
+// The result of f is either a cue.Value created with the same context or the
+// source of the package, as a string, []byte, io.Reader, or *ast.File. The
+// source is built as a package with the given import path. If it has no
+// package clause, the package name is derived from the import path.
+//
+// Results are cached, so f is called at most once per import path for the
+// lifetime of the context. An error returned by f is reported as an error
+// at the position of the import declaration.
+func ImportResolver(f func(importPath string) (interface{}, error)) Option {
+	return importResolver(f)
+}
+
+type importResolver func(importPath string) (interface{}, error)
+
+func (importResolver) buildOption() {}
+
+func (f importResolver) runtimeResolver(r *runtime.Runtime) runtime.ImportResolver {
+	return func(importPath string) (interface{}, error) {
+		src, err := f(importPath)
+		if err != nil {
+			return nil, err
+		}
+		if v, ok := src.(cue.Value); ok {
+			vr, x := value.ToInternal(v)
+			if vr != r {
+				return nil, fmt.Errorf("value not created with the same context")
+			}
+			if err := v.Err(); err != nil {
+				return nil, err
+			}
+			return x, nil
+		}
+		return src, nil
+	}
+}
@@ -16,10 +16,12 @@ package cuecontext
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"cuelang.org/go/cue"
 	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/errors"
 )
 
 func TestAPI(t *testing.T) {
@@ -72,3 +74,98 @@ func TestConcurrency(t *testing.T) {
 		`)
 	}()
 }
+
+func TestImportResolver(t *testing.T) {
+	calls := map[string]int{}
+	var ctx *cue.Context
+	ctx = New(ImportResolver(func(importPath string) (interface{}, error) {
+		calls[importPath]++
+		switch importPath {
+		case "registry.example/schemas/server":
+			return `
+			package server
+
+			import "registry.example/schemas/port"
+
+			#Server: {host: string, listen: port.#Port}
+			`, nil
+		case "registry.example/schemas/port":
+			// No package clause: the name is derived from the import path.
+			return `#Port: int & >0 & <65536`, nil
+		case "registry.example/values/defaults":
+			return ctx.CompileString(`timeout: "5s"`), nil
+		case "registry.example/schemas/loop":
+			return `
+			package loop
+
+			import "registry.example/schemas/loop"
+
+			a: loop.a
+			`, nil
+		}
+		return nil, fmt.Errorf("not in registry")
+	}))
+
+	v := ctx.CompileString(`
+	import (
+		"strings"
+		"registry.example/schemas/server"
+		"registry.example/values/defaults"
+	)
+
+	s: server.#Server & {host: strings.ToLower("LOCALHOST"), listen: 8080}
+	t: defaults.timeout
+	`)
+	if err := v.Validate(cue.Concrete(true)); err != nil {
+		t.Fatal(errors.Details(err, nil))
+	}
+	if got, want := fmt.Sprint(v), `{
+	s: {
+		host:   "localhost"
+		listen: 8080
+	}
+	t: "5s"
+}`; got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Results are cached per context.
+	v = ctx.CompileString(`
+	import "registry.example/schemas/server"
+
+	s: server.#Server & {host: "x", listen: 0}
+	`)
+	if v.Err() == nil {
+		if err := v.Validate(); err == nil {
+			t.Error("expected error for invalid port")
+		}
+	}
+	for path, n := range calls {
+		if n != 1 {
+			t.Errorf("resolver called %d times for %s; want 1", n, path)
+		}
+	}
+
+	// Resolver errors are reported at the import.
+	v = ctx.CompileString(`
+	import "registry.example/missing"
+
+	a: missing.a
+	`, cue.Filename("in.cue"))
+	const want = `cannot resolve import "registry.example/missing": not in registry`
+	if err := v.Err(); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("got error %v; want %q", err, want)
+	} else if pos := errors.Positions(err); len(pos) == 0 || pos[0].String() != "in.cue:2:9" {
+		t.Errorf("got positions %v; want in.cue:2:9", pos)
+	}
+
+	// Import cycles through resolved packages are detected.
+	v = ctx.CompileString(`
+	import "registry.example/schemas/loop"
+
+	a: loop.a
+	`)
+	if err := v.Err(); err == nil || !strings.Contains(errors.Details(err, nil), "import cycle") {
+		t.Errorf("got error %v; want import cycle", err)
+	}
+}
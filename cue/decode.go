@@ -17,7 +17,9 @@ package cue
 import (
 	"bytes"
 	"encoding"
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"reflect"
 	"sort"
 	"strconv"
@@ -32,8 +34,36 @@ import (
 
 // Decode initializes x with Value v. If x is a struct, it will validate the
 // constraints specified in the field tags.
-func (v Value) Decode(x interface{}) error {
-	var d decoder
+//
+// A string field of a Go struct with the tag `cue:",path"` is set to the path
+// of the CUE value from which the struct is decoded, as reported by Path. This
+// can be used, for instance, to relate errors found in decoded values back to
+// the configuration.
+//
+// A disjunction is decoded as its default value, if any. This allows decoding
+// an enumeration of scalar values, such as "a" | "b" | "c", into a Go type with
+// a corresponding underlying type. A disjunction of concrete scalar values that
+// could not be resolved to a single value is reported as an error that lists the
+// allowed values.
+//
+// A bytes value decodes into a Go []byte or byte array as is. A string value
+// decodes into these types as the raw bytes of the string. Decoding a bytes
+// value into a Go string is an error, unless the BytesAsBase64 option is given,
+// in which case the bytes are encoded as standard base64.
+//
+// A CUE struct may be decoded into a Go slice of structs with two fields: a
+// string field with the tag `cue:",key"` and a field with the tag
+// `cue:",value"`. Each element then holds the label and decoded value of one
+// field, in the order in which the fields appear in v. Unlike decoding into a
+// map, this preserves field order.
+//
+// A Value within x, such as a struct field of type Value, is set to the
+// corresponding sub-value of v as is, without further decoding or checking for
+// concreteness. Similarly, a json.RawMessage is set to the JSON encoding of the
+// corresponding sub-value. This allows decoding the known parts of a value
+// while keeping the remainder opaque for later processing.
+func (v Value) Decode(x interface{}, opts ...Option) error {
+	d := decoder{opts: getOptions(opts)}
 	w := reflect.ValueOf(x)
 	switch {
 	case !reflect.Indirect(w).CanSet():
@@ -49,6 +79,7 @@ func (v Value) Decode(x interface{}) error {
 }
 
 type decoder struct {
+	opts options
 	errs errors.Error
 }
 
@@ -68,18 +99,55 @@ func incompleteError(v Value) errors.Error {
 	}
 }
 
+// enumValues returns the disjuncts of v if v is a disjunction of concrete
+// scalar values, or nil otherwise.
+func enumValues(v Value) []Value {
+	op, a := v.Expr()
+	if op != OrOp {
+		return nil
+	}
+	for _, x := range a {
+		if !x.IsConcrete() || x.IncompleteKind()&(StructKind|ListKind) != 0 {
+			return nil
+		}
+	}
+	return a
+}
+
+func enumError(v Value, a []Value) errors.Error {
+	s := make([]string, len(a))
+	for i, x := range a {
+		s[i] = fmt.Sprint(x)
+	}
+	return &valueError{
+		v: v,
+		err: &adt.Bottom{
+			Code: adt.IncompleteError,
+			Err: errors.Newf(v.Pos(),
+				"cannot convert non-concrete value: must be one of %s",
+				strings.Join(s, ", "))},
+	}
+}
+
 func (d *decoder) clear(x reflect.Value) {
 	if x.CanSet() {
 		x.Set(reflect.Zero(x.Type()))
 	}
 }
 
+var valueType = reflect.TypeOf(Value{})
+
 func (d *decoder) decode(x reflect.Value, v Value, isPtr bool) {
 	if !x.IsValid() {
 		d.addErr(errors.Newf(v.Pos(), "cannot decode into invalid value"))
 		return
 	}
 
+	if x.Type() == valueType {
+		x.Set(reflect.ValueOf(v))
+		return
+	}
+
 	v, _ = v.Default()
 	if v.v == nil {
 		d.clear(x)
@@ -102,6 +170,10 @@ func (d *decoder) decode(x reflect.Value, v Value, isPtr bool) {
 	default:
 		// TODO: allow incomplete values.
 		if !v.IsConcrete() {
+			if a := enumValues(v); a != nil {
+				d.addErr(enumError(v, a))
+				return
+			}
 			d.addErr(incompleteError(v))
 			return
 		}
@@ -172,6 +244,17 @@ func (d *decoder) decode(x reflect.Value, v Value, isPtr bool) {
 		x.SetFloat(f)
 
 	case reflect.String:
+		if v.Kind() == BytesKind {
+			if !d.opts.bytesAsBase64 {
+				d.addErr(errors.Newf(v.Pos(),
+					"cannot decode bytes value %v into Go string; use the BytesAsBase64 option to encode it as base64", v))
+				break
+			}
+			b, err := v.Bytes()
+			d.addErr(err)
+			x.SetString(base64.StdEncoding.EncodeToString(b))
+			break
+		}
 		s, err := v.String()
 		d.addErr(err)
 		x.SetString(s)
@@ -182,7 +265,7 @@ func (d *decoder) decode(x reflect.Value, v Value, isPtr bool) {
 		t := x.Type()
 		n := x.Len()
 
-		if t.Elem().Kind() == reflect.Uint8 && v.Kind() == BytesKind {
+		if t.Elem().Kind() == reflect.Uint8 && isBytesOrString(v) {
 			b, err := v.Bytes()
 			d.addErr(err)
 			for i, c := range b {
@@ -210,13 +293,18 @@ func (d *decoder) decode(x reflect.Value, v Value, isPtr bool) {
 
 	case reflect.Slice:
 		t := x.Type()
-		if t.Elem().Kind() == reflect.Uint8 && v.Kind() == BytesKind {
+		if t.Elem().Kind() == reflect.Uint8 && isBytesOrString(v) {
 			b, err := v.Bytes()
 			d.addErr(err)
 			x.SetBytes(b)
 			break
 		}
 
+		if key, value, ok := keyValueFields(t.Elem()); ok && v.Kind() == StructKind {
+			d.convertKeyValues(x, v, key, value)
+			break
+		}
+
 		var a []Value
 		list, err := v.List()
 		d.addErr(err)
@@ -248,6 +336,11 @@ func (d *decoder) decode(x reflect.Value, v Value, isPtr bool) {
 	}
 }
 
+func isBytesOrString(v Value) bool {
+	k := v.Kind()
+	return k == BytesKind || k == StringKind
+}
+
 func (d *decoder) interfaceValue(v Value) (x interface{}) {
 	var err error
 	v, _ = v.Default()
@@ -384,6 +477,49 @@ func (d *decoder) convertMap(x reflect.Value, v Value) {
 	}
 }
 
+// keyValueFields reports whether t is a struct of exactly two fields, a string
+// field tagged `cue:",key"` and a field tagged `cue:",value"`, and returns
+// their indices if so.
+func keyValueFields(t reflect.Type) (key, value int, ok bool) {
+	if t.Kind() != reflect.Struct || t.NumField() != 2 {
+		return 0, 0, false
+	}
+	key, value = -1, -1
+	for i := 0; i < 2; i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			return 0, 0, false
+		}
+		_, opts := parseTag(sf.Tag.Get("cue"))
+		switch {
+		case opts.Contains("key") && sf.Type.Kind() == reflect.String:
+			key = i
+		case opts.Contains("value"):
+			value = i
+		}
+	}
+	return key, value, key >= 0 && value >= 0
+}
+
+// convertKeyValues decodes the fields of struct v into the slice x of
+// key-value structs, preserving the order in which the fields are defined.
+func (d *decoder) convertKeyValues(x reflect.Value, v Value, key, value int) {
+	t := x.Type()
+
+	iter, err := v.Fields()
+	d.addErr(err)
+
+	a := reflect.MakeSlice(t, 0, x.Cap())
+	elem := reflect.New(t.Elem()).Elem()
+	for iter.Next() {
+		elem.Set(reflect.Zero(t.Elem()))
+		elem.Field(key).SetString(iter.Label())
+		d.decode(elem.Field(value), iter.Value(), false)
+		a = reflect.Append(a, elem)
+	}
+	x.Set(a)
+}
+
 func (d *decoder) convertStruct(x reflect.Value, v Value) {
 	t := x.Type()
 	fields := cachedTypeFields(t)
@@ -415,28 +551,7 @@ func (d *decoder) convertStruct(x reflect.Value, v Value) {
 		}
 
 		// Figure out field corresponding to key.
-		subv := x
-		for _, i := range f.index {
-			if subv.Kind() == reflect.Ptr {
-				if subv.IsNil() {
-					// If a struct embeds a pointer to an unexported type,
-					// it is not possible to set a newly allocated value
-					// since the field is unexported.
-					//
-					// See https://golang.org/issue/21357
-					if !subv.CanSet() {
-						d.addErr(errors.Newf(v.Pos(),
-							"cannot set embedded pointer to unexported struct: %v",
-							subv.Type().Elem()))
-						subv = reflect.Value{}
-						break
-					}
-					subv.Set(reflect.New(subv.Type().Elem()))
-				}
-				subv = subv.Elem()
-			}
-			subv = subv.Field(i)
-		}
+		subv := d.fieldByIndex(x, f.index, v)
 
 		// TODO: make this an option
 		//  else if d.disallowUnknownFields {
@@ -445,11 +560,51 @@ func (d *decoder) convertStruct(x reflect.Value, v Value) {
 
 		d.decode(subv, iter.Value(), false)
 	}
+
+	if len(fields.paths) > 0 {
+		path := v.Path().String()
+		for _, index := range fields.paths {
+			if subv := d.fieldByIndex(x, index, v); subv.IsValid() {
+				subv.SetString(path)
+			}
+		}
+	}
+}
+
+// fieldByIndex returns the nested field of x corresponding to index,
+// allocating embedded pointers to structs as needed.
+func (d *decoder) fieldByIndex(x reflect.Value, index []int, v Value) reflect.Value {
+	subv := x
+	for _, i := range index {
+		if subv.Kind() == reflect.Ptr {
+			if subv.IsNil() {
+				// If a struct embeds a pointer to an unexported type,
+				// it is not possible to set a newly allocated value
+				// since the field is unexported.
+				//
+				// See https://golang.org/issue/21357
+				if !subv.CanSet() {
+					d.addErr(errors.Newf(v.Pos(),
+						"cannot set embedded pointer to unexported struct: %v",
+						subv.Type().Elem()))
+					return reflect.Value{}
+				}
+				subv.Set(reflect.New(subv.Type().Elem()))
+			}
+			subv = subv.Elem()
+		}
+		subv = subv.Field(i)
+	}
+	return subv
 }
 
 type structFields struct {
 	list      []goField
 	nameIndex map[string]int
+
+	// paths holds the indices of string fields with a `cue:",path"` tag,
+	// which are set to the path of the decoded value.
+	paths [][]int
 }
 
 func isValidTag(s string) bool {
@@ -519,6 +674,7 @@ func typeFields(t reflect.Type) structFields {
 
 	// Fields found.
 	var fields []goField
+	var paths [][]int
 
 	// Buffer to run HTMLEscape on field names.
 	var nameEscBuf bytes.Buffer
@@ -552,6 +708,16 @@ func typeFields(t reflect.Type) structFields {
 					// Ignore unexported non-embedded fields.
 					continue
 				}
+				index := make([]int, len(f.index)+1)
+				copy(index, f.index)
+				index[len(f.index)] = i
+
+				if _, opts := parseTag(sf.Tag.Get("cue")); opts.Contains("path") &&
+					sf.Type.Kind() == reflect.String {
+					paths = append(paths, index)
+					continue
+				}
+
 				tag := sf.Tag.Get("json")
 				if tag == "-" {
 					continue
@@ -560,9 +726,6 @@ func typeFields(t reflect.Type) structFields {
 				if !isValidTag(name) {
 					name = ""
 				}
-				index := make([]int, len(f.index)+1)
-				copy(index, f.index)
-				index[len(f.index)] = i
 
 				ft := sf.Type
 				if ft.Name() == "" && ft.Kind() == reflect.Ptr {
@@ -666,7 +829,7 @@ func typeFields(t reflect.Type) structFields {
 	for i, field := range fields {
 		nameIndex[field.name] = i
 	}
-	return structFields{fields, nameIndex}
+	return structFields{fields, nameIndex, paths}
 }
 
 // dominantField looks through the fields, all of which are known to
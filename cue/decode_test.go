@@ -15,6 +15,8 @@
 package cue
 
 import (
+	"encoding/json"
+	"fmt"
 	"reflect"
 	"testing"
 	"time"
@@ -33,6 +35,12 @@ func TestDecode(t *testing.T) {
 		M map[string]interface{}
 		*Nested
 	}
+	type color string
+	type level int
+	type enums struct {
+		C color
+		L level
+	}
 	one := 1
 	intList := func(ints ...int) *[]int {
 		ints = append([]int{}, ints...)
@@ -40,6 +48,7 @@ func TestDecode(t *testing.T) {
 	}
 	testCases := []struct {
 		value string
+		opts  []Option
 		dst   interface{}
 		want  interface{}
 		err   string
@@ -98,6 +107,28 @@ func TestDecode(t *testing.T) {
 		value: `'bytes'`,
 		dst:   &[3]byte{},
 		want:  [3]byte{0x62, 0x79, 0x74},
+	}, {
+		value: `"str"`,
+		dst:   new([]byte),
+		want:  []byte("str"),
+	}, {
+		value: `"str"`,
+		dst:   &[2]byte{},
+		want:  [2]byte{0x73, 0x74},
+	}, {
+		value: `'bytes\x00'`,
+		dst:   new(string),
+		err:   `cannot decode bytes value 'bytes\x00' into Go string; use the BytesAsBase64 option to encode it as base64`,
+	}, {
+		value: `'bytes\x00'`,
+		opts:  []Option{BytesAsBase64(true)},
+		dst:   new(string),
+		want:  "Ynl0ZXMA",
+	}, {
+		value: `{a: 'bytes', b: "str"}`,
+		opts:  []Option{BytesAsBase64(true)},
+		dst:   &map[string]string{},
+		want:  map[string]string{"a": "Ynl0ZXM=", "b": "str"},
 	}, {
 		value: `1`,
 		dst:   new(float32),
@@ -231,10 +262,30 @@ func TestDecode(t *testing.T) {
 				`,
 		dst: &S{},
 		err: "Decode: x: cannot use value 1 (type int) as (string|bytes)",
+	}, {
+		value: `*"red" | "green" | "blue"`,
+		dst:   new(color),
+		want:  color("red"),
+	}, {
+		value: `{c: "red" | "green", c: "green", l: 1 | 2, l: 2}`,
+		dst:   new(enums),
+		want:  enums{C: "green", L: 2},
+	}, {
+		value: `"red" | "green" | "blue"`,
+		dst:   new(color),
+		err:   `cannot convert non-concrete value: must be one of "red", "green", "blue"`,
+	}, {
+		value: `{c: "red", l: 1 | 2 | 3}`,
+		dst:   new(enums),
+		err:   `l: cannot convert non-concrete value: must be one of 1, 2, 3`,
+	}, {
+		value: `"red" | string`,
+		dst:   new(color),
+		err:   `cannot convert non-concrete value "red" | string`,
 	}}
 	for _, tc := range testCases {
 		t.Run(tc.value, func(t *testing.T) {
-			err := getInstance(t, tc.value).Value().Decode(tc.dst)
+			err := getInstance(t, tc.value).Value().Decode(tc.dst, tc.opts...)
 			checkFatal(t, err, tc.err, "init")
 
 			got := reflect.ValueOf(tc.dst).Elem().Interface()
@@ -246,6 +297,232 @@ func TestDecode(t *testing.T) {
 	}
 }
 
+func TestDecodeEmbedded(t *testing.T) {
+	type Base struct {
+		ID   int
+		Name string `json:"name"`
+		Kind string
+	}
+	type Tagged struct {
+		Kind string `json:"Kind"`
+	}
+	type Mid struct {
+		*Base
+		Level int
+		Kind  string // shadows Base.Kind
+	}
+	type Other struct {
+		Level int // conflicts with Mid.Level at the same depth
+	}
+	type Top struct {
+		Mid
+		Other
+		Tagged
+		Name string `json:"name"` // shadows Base.Name
+	}
+	type Renamed struct {
+		Base `json:"base"`
+	}
+
+	testCases := []struct {
+		value string
+		dst   interface{}
+		want  interface{}
+	}{{
+		// Fields of embedded structs are promoted across multiple levels,
+		// allocating embedded pointers as needed. Shallower fields win over
+		// deeper ones, tagged fields win over untagged ones at the same depth,
+		// and remaining conflicts at the same depth are ignored.
+		value: `{ID: 1, name: "top", Kind: "tagged", Level: 2}`,
+		dst:   &Top{},
+		want: &Top{
+			Mid:    Mid{Base: &Base{ID: 1}},
+			Tagged: Tagged{Kind: "tagged"},
+			Name:   "top",
+		},
+	}, {
+		value: `{ID: 1, name: "base", Kind: "mid"}`,
+		dst:   &Mid{},
+		want:  &Mid{Base: &Base{ID: 1, Name: "base"}, Kind: "mid"},
+	}, {
+		// An embedded struct with a name is not promoted.
+		value: `{ID: 1, base: {ID: 2, name: "base"}}`,
+		dst:   &Renamed{},
+		want:  &Renamed{Base: Base{ID: 2, Name: "base"}},
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			v := getInstance(t, tc.value).Value()
+			if err := v.Decode(tc.dst); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.dst, tc.want); diff != "" {
+				t.Error(diff)
+			}
+
+			// Check that the result is identical to that of encoding/json.
+			b, err := v.MarshalJSON()
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := reflect.New(reflect.TypeOf(tc.dst).Elem())
+			if err := json.Unmarshal(b, want.Interface()); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.dst, want.Interface()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestDecodeRaw(t *testing.T) {
+	type config struct {
+		Name   string          `json:"name"`
+		Extra  Value           `json:"extra"`
+		Schema Value           `json:"schema"`
+		JSON   json.RawMessage `json:"json"`
+	}
+	v := getInstance(t, `
+		name: "foo"
+		extra: {a: 1, b: [1, 2]}
+		schema: {a: int, b?: string}
+		json: {a: 1, b: [1, 2]}
+		`).Value()
+
+	var c config
+	if err := v.Decode(&c); err != nil {
+		t.Fatal(err)
+	}
+	if c.Name != "foo" {
+		t.Errorf("name: got %q; want %q", c.Name, "foo")
+	}
+	if got, want := fmt.Sprint(c.Extra), fmt.Sprint(v.LookupPath(ParsePath("extra"))); got != want {
+		t.Errorf("extra: got %s; want %s", got, want)
+	}
+	var extra struct {
+		A int   `json:"a"`
+		B []int `json:"b"`
+	}
+	if err := c.Extra.Decode(&extra); err != nil {
+		t.Fatal(err)
+	}
+	if extra.A != 1 || len(extra.B) != 2 {
+		t.Errorf("extra: got %+v", extra)
+	}
+	// Non-concrete values may be decoded into a Value.
+	if got, want := c.Schema.IncompleteKind(), StructKind; got != want {
+		t.Errorf("schema: got kind %v; want %v", got, want)
+	}
+	if got, want := string(c.JSON), `{"a":1,"b":[1,2]}`; got != want {
+		t.Errorf("json: got %s; want %s", got, want)
+	}
+}
+
+func TestDecodeKeyValues(t *testing.T) {
+	type entry struct {
+		Name  string `cue:",key"`
+		Value []int  `cue:",value"`
+	}
+	type pair struct {
+		Key   string      `cue:",key"`
+		Value interface{} `cue:",value"`
+	}
+	testCases := []struct {
+		value string
+		dst   interface{}
+		want  interface{}
+		err   string
+	}{{
+		// Fields are decoded in source order, not sorted by label.
+		value: `{z: [1], a: [2, 3], m: []}`,
+		dst:   new([]entry),
+		want: &[]entry{
+			{"z", []int{1}},
+			{"a", []int{2, 3}},
+			{"m", []int{}},
+		},
+	}, {
+		value: `{b: 1, a: "x", c: {d: true}}`,
+		dst:   &[]pair{{"old", 1}, {"old", 2}, {"old", 3}, {"old", 4}},
+		want: &[]pair{
+			{"b", 1},
+			{"a", "x"},
+			{"c", map[string]interface{}{"d": true}},
+		},
+	}, {
+		value: `{}`,
+		dst:   new([]pair),
+		want:  &[]pair{},
+	}, {
+		// Lists are still decoded element by element.
+		value: `[{Key: "a", Value: 1}]`,
+		dst:   new([]pair),
+		want:  &[]pair{{"a", 1}},
+	}, {
+		value: `{a: [1], b: "x"}`,
+		dst:   new([]entry),
+		err:   "cannot use value \"x\" (type string) as list",
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			err := getInstance(t, tc.value).Value().Decode(tc.dst)
+			checkFatal(t, err, tc.err, "init")
+			if tc.err != "" {
+				return
+			}
+			if diff := cmp.Diff(tc.dst, tc.want); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestDecodePath(t *testing.T) {
+	type record struct {
+		Path  string `cue:",path"`
+		Name  string `json:"name"`
+		Other int    `cue:",path"` // ignored: not a string
+	}
+	type config struct {
+		Path    string            `cue:",path"`
+		Records []record          `json:"records"`
+		ByName  map[string]record `json:"byName"`
+		Single  *record           `json:"single"`
+	}
+	v := getInstance(t, `
+		records: [{name: "a"}, {name: "b", Other: 3}]
+		byName: x: name: "c"
+		single: name: "d"
+		`).Value()
+
+	var c config
+	if err := v.Decode(&c); err != nil {
+		t.Fatal(err)
+	}
+	want := config{
+		Path: "",
+		Records: []record{
+			{Path: "records[0]", Name: "a"},
+			{Path: "records[1]", Name: "b", Other: 3},
+		},
+		ByName: map[string]record{"x": {Path: "byName.x", Name: "c"}},
+		Single: &record{Path: "single", Name: "d"},
+	}
+	if diff := cmp.Diff(want, c); diff != "" {
+		t.Error(diff)
+	}
+
+	// Decoding a sub-value reports the full path.
+	var r record
+	if err := v.LookupPath(ParsePath("byName.x")).Decode(&r); err != nil {
+		t.Fatal(err)
+	}
+	if r.Path != "byName.x" {
+		t.Errorf("got path %q; want %q", r.Path, "byName.x")
+	}
+}
+
 type Duration struct {
 	D time.Duration
 }
@@ -63,6 +63,43 @@ func IndentPrefix(n int) Option {
 	return func(c *config) { c.Indent = n }
 }
 
+// A SeparatorStyle defines how the fields of struct literals are separated.
+type SeparatorStyle int
+
+const (
+	// KeepSeparators retains the separators of the input. This is the
+	// default.
+	KeepSeparators SeparatorStyle = iota
+
+	// NewlineSeparators places each field of a struct literal with more than
+	// one field on its own line.
+	NewlineSeparators
+
+	// CommaSeparators places the fields of a struct literal on a single line,
+	// separated by commas, if its single-line form, including indentation,
+	// fits within the width set by Width. Otherwise, each field is placed on
+	// its own line. Struct literals containing comments or fields separated
+	// by blank lines are never placed on a single line.
+	CommaSeparators
+
+	// oneLineSeparators places the fields of all struct literals on a single
+	// line. It is used to determine whether a struct fits on one line.
+	oneLineSeparators
+)
+
+// Separators normalizes the separators between the fields of struct literals
+// according to the given style. Blank lines between fields and comments are
+// retained.
+func Separators(s SeparatorStyle) Option {
+	return func(c *config) { c.separators = s }
+}
+
+// Width sets the maximum line width considered by CommaSeparators. The
+// default is 80.
+func Width(n int) Option {
+	return func(c *config) { c.width = n }
+}
+
 // TODO: make public
 // sortImportsOption causes import declarations to be sorted.
 func sortImportsOption() Option {
@@ -124,6 +161,8 @@ type config struct {
 
 	simplify    bool
 	sortImports bool
+	separators  SeparatorStyle
+	width       int
 }
 
 func newConfig(opt []Option) *config {
@@ -131,6 +170,7 @@ func newConfig(opt []Option) *config {
 		Tabwidth:  8,
 		TabIndent: true,
 		UseSpaces: true,
+		width:     80,
 	}
 	for _, o := range opt {
 		o(cfg)
@@ -237,6 +277,10 @@ type settings struct {
 	nodeSep   whiteSpace
 	parentSep whiteSpace
 	override  whiteSpace
+
+	// fieldSep, if not ignore, is the separator enforced between the fields
+	// of the current struct literal. See SeparatorStyle.
+	fieldSep whiteSpace
 }
 
 // suppress spurious linter warning: field is actually used.
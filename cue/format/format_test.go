@@ -48,6 +48,8 @@ const (
 	idempotent
 	simplify
 	sortImps
+	newlineSeps
+	commaSeps
 )
 
 // format parses src, prints the corresponding AST, verifies the resulting
@@ -62,6 +64,12 @@ func format(src []byte, mode checkMode) ([]byte, error) {
 	if mode&sortImps != 0 {
 		opts = append(opts, sortImportsOption())
 	}
+	if mode&newlineSeps != 0 {
+		opts = append(opts, Separators(NewlineSeparators))
+	}
+	if mode&commaSeps != 0 {
+		opts = append(opts, Separators(CommaSeparators), Width(40))
+	}
 
 	res, err := Source(src, opts...)
 	if err != nil {
@@ -198,6 +206,8 @@ var data = []entry{
 	{"expressions.input", "expressions.golden", 0},
 	{"values.input", "values.golden", 0},
 	{"imports.input", "imports.golden", sortImps},
+	{"separators.input", "separators_newline.golden", newlineSeps | idempotent},
+	{"separators.input", "separators_comma.golden", commaSeps | idempotent},
 }
 
 func TestFiles(t *testing.T) {
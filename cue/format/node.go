@@ -15,8 +15,10 @@
 package format
 
 import (
+	"bytes"
 	"fmt"
 	"strings"
+	"unicode/utf8"
 
 	"cuelang.org/go/cue/ast"
 	"cuelang.org/go/cue/literal"
@@ -132,6 +134,9 @@ func (f *formatter) walkDeclList(list []ast.Decl) {
 			hasEllipsis = true
 			continue
 		}
+		if i > 0 {
+			f.fieldSeparator(x)
+		}
 		f.decl(x)
 		d = 0
 		if f, ok := x.(*ast.Field); ok {
@@ -164,6 +169,76 @@ func (f *formatter) walkDeclList(list []ast.Decl) {
 	f.after(nil)
 }
 
+// fieldSeparator enforces the separator before d, which is not the first
+// declaration of a list, if the current struct literal has one.
+func (f *formatter) fieldSeparator(d ast.Decl) {
+	switch f.current.fieldSep {
+	case newline:
+		if d.Pos().RelPos() >= token.NewSection {
+			f.print(newsection | nooverride)
+		} else {
+			f.print(newline | nooverride)
+		}
+	case blank:
+		f.allowed &^= newline | formfeed | newsection
+		f.print(blank | nooverride)
+	}
+}
+
+// structSeparator reports the separator to enforce between the fields of x
+// for the configured SeparatorStyle, or ignore if the separators of the input
+// should be retained.
+func (f *formatter) structSeparator(x *ast.StructLit) whiteSpace {
+	switch f.cfg.separators {
+	case NewlineSeparators:
+		if len(x.Elts) > 1 {
+			return newline
+		}
+	case CommaSeparators:
+		if len(x.Elts) > 0 {
+			if f.fitsOnOneLine(x) {
+				return blank
+			}
+			return newline
+		}
+	case oneLineSeparators:
+		return blank
+	}
+	return ignore
+}
+
+// fitsOnOneLine reports whether x can be printed on a single line within the
+// configured width. This is not the case if x contains comments or fields
+// separated by blank lines.
+func (f *formatter) fitsOnOneLine(x *ast.StructLit) bool {
+	multiline := false
+	ast.Walk(x, func(n ast.Node) bool {
+		if len(n.Comments()) > 0 {
+			multiline = true
+		}
+		if s, ok := n.(*ast.StructLit); ok {
+			for i, d := range s.Elts {
+				if i > 0 && d.Pos().RelPos() >= token.NewSection {
+					multiline = true
+				}
+			}
+		}
+		return !multiline
+	}, nil)
+	if multiline {
+		return false
+	}
+
+	cfg := *f.cfg
+	cfg.separators = oneLineSeparators
+	b, err := cfg.fprint(x)
+	if err != nil || bytes.IndexByte(b, '\n') >= 0 {
+		return false
+	}
+	indent := (f.cfg.Indent + f.indent) * f.cfg.Tabwidth
+	return indent+utf8.RuneCount(b) <= f.cfg.width
+}
+
 func (f *formatter) walkSpecList(list []*ast.ImportSpec) {
 	f.before(nil)
 	for _, x := range list {
@@ -302,7 +377,7 @@ func (f *formatter) decl(decl ast.Decl) {
 			return
 		}
 
-		nextFF := f.nextNeedsFormfeed(n.Value)
+		nextFF := f.nextNeedsFormfeed(n.Value) && f.current.fieldSep != blank
 		tab := vtab
 		if nextFF {
 			tab = blank
@@ -628,9 +703,19 @@ func (f *formatter) exprRaw(expr ast.Expr, prec1, depth int) {
 	case *ast.StructLit:
 		var l line
 		ws := noblank
+		sep := f.structSeparator(x)
+		f.current.fieldSep = sep
+		if sep == blank {
+			f.current.nodeSep = blank
+		}
 		ff := f.formfeed()
 
 		switch {
+		case sep != ignore:
+			ws |= nooverride
+			if sep == newline {
+				ws |= newline
+			}
 		case len(x.Elts) == 0:
 			if !x.Rbrace.HasRelPos() {
 				// collapse curly braces if the body is empty.
@@ -651,6 +736,9 @@ func (f *formatter) exprRaw(expr ast.Expr, prec1, depth int) {
 		f.matchUnindent()
 
 		ws = noblank
+		if sep != ignore {
+			ws |= nooverride
+		}
 		if f.lineout != l {
 			ws |= newline
 			if f.lastTok != token.RBRACE && f.lastTok != token.RBRACK {
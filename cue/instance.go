@@ -18,6 +18,7 @@ import (
 	"cuelang.org/go/cue/ast"
 	"cuelang.org/go/cue/build"
 	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/token"
 	"cuelang.org/go/internal"
 	"cuelang.org/go/internal/core/adt"
 	"cuelang.org/go/internal/core/compile"
@@ -197,6 +198,16 @@ func (inst *Instance) ID() string {
 	return inst.inst.ID()
 }
 
+// Meta returns the value associated with key in the build instance from which
+// inst was created, as set by build.Instance.SetMeta, or nil if there is no
+// such value.
+func (inst *Instance) Meta(key interface{}) interface{} {
+	if inst == nil || inst.inst == nil {
+		return nil
+	}
+	return inst.inst.Meta(key)
+}
+
 // Doc returns the package comments for this instance.
 //
 // Deprecated: use inst.Value().Doc()
@@ -218,6 +229,55 @@ func (inst *Instance) Value() Value {
 	return newVertexRoot(inst.index, ctx, inst.root)
 }
 
+// DefInfo describes a top-level definition of an instance.
+type DefInfo struct {
+	// Name is the name of the definition, including the leading # or _#.
+	Name string
+
+	// Hidden reports whether this is a hidden definition, such as _#Foo.
+	Hidden bool
+
+	// Value is the value of the definition. Definitions nested within this
+	// definition can be obtained through this value.
+	Value Value
+
+	// Doc holds the documentation comments associated with the definition.
+	Doc []*ast.CommentGroup
+
+	// Attrs holds the field and declaration attributes of the definition.
+	Attrs []Attribute
+
+	// Pos is the position of the definition.
+	Pos token.Pos
+}
+
+// Definitions returns all top-level definitions of the instance, including
+// hidden definitions, in source order.
+func (inst *Instance) Definitions() []DefInfo {
+	v := inst.Value()
+	iter, err := v.Fields(Definitions(true), Hidden(true), Optional(true))
+	if err != nil {
+		return nil
+	}
+	var defs []DefInfo
+	for iter.Next() {
+		sel := iter.Selector()
+		if !sel.Type().IsDefinition() {
+			continue
+		}
+		w := iter.Value()
+		defs = append(defs, DefInfo{
+			Name:   sel.String(),
+			Hidden: sel.Type().IsHidden(),
+			Value:  w,
+			Doc:    w.Doc(),
+			Attrs:  w.Attributes(FieldAttr | DeclAttr),
+			Pos:    w.Pos(),
+		})
+	}
+	return defs
+}
+
 // Eval evaluates an expression within an existing instance.
 //
 // Expressions may refer to builtin packages if they can be uniquely identified.
@@ -21,12 +21,15 @@ import (
 	"path/filepath"
 	"strings"
 
+	"golang.org/x/mod/semver"
+
 	"cuelang.org/go/cue/ast"
 	"cuelang.org/go/cue/build"
 	"cuelang.org/go/cue/errors"
 	"cuelang.org/go/cue/parser"
 	"cuelang.org/go/cue/token"
 	"cuelang.org/go/internal"
+	"cuelang.org/go/internal/core/adt"
 	"cuelang.org/go/internal/core/compile"
 	"cuelang.org/go/internal/core/eval"
 	"cuelang.org/go/internal/core/runtime"
@@ -143,6 +146,23 @@ type Config struct {
 	// the module field of an existing cue.mod file.
 	Module string
 
+	// Workspace, if non-empty, is the path of a workspace file, conventionally
+	// named cue.work, that lists the root directories of other modules whose
+	// packages may be imported without vendoring them. A relative path is
+	// interpreted relative to Dir. The file is a CUE file of the form
+	//
+	//     modules: ["./frontend", "./backend"]
+	//
+	// where each entry is a directory relative to the workspace file. Each
+	// listed module must declare its module path in its cue.mod file.
+	// Imports of packages within a listed module resolve to that module's
+	// directory. Packages of modules that are not listed cannot be imported
+	// this way. It is an error for two modules to declare the same module
+	// path or to require different language versions.
+	Workspace string
+
+	workspace []*workspaceModule
+
 	// Package defines the name of the package to be loaded. If this is not set,
 	// the package must be uniquely defined from its context. Special values:
 	//    _    load files without a package
@@ -268,6 +288,19 @@ type Config struct {
 	// the syntax tree.
 	ParseFile func(name string, src interface{}) (*ast.File, error)
 
+	// Preprocess, if non-nil, is called with the contents of each CUE file
+	// before it is parsed, including when determining its package and
+	// imports. The returned bytes are used in place of the original contents.
+	// It must be safe to call Preprocess simultaneously from multiple
+	// goroutines.
+	//
+	// Positions in the parsed files, and hence in any error messages, refer
+	// to the transformed contents rather than to the original file. An error
+	// returned by Preprocess is reported as an error for the file at path.
+	//
+	// Preprocess is not called for overlays that specify a syntax tree.
+	Preprocess func(path string, src []byte) ([]byte, error)
+
 	// Overlay provides a mapping of absolute file paths to file contents.  If
 	// the file with the given path already exists, the parser will use the
 	// alternative file contents provided by the map.
@@ -277,11 +310,33 @@ type Config struct {
 	// the corresponding build.File will be associated with the full buffer.
 	Stdin io.Reader
 
+	// StdinFilename, if non-empty, is the file name under which the contents
+	// of the file "-" are reported, for instance in positions and error
+	// messages. A relative name is interpreted relative to Dir. Imports are
+	// resolved as for any other file in Dir.
+	StdinFilename string
+
+	// Warn, if non-nil, is called for problems that do not prevent loading,
+	// such as a module requiring a newer language version than is supported
+	// by this implementation. If Warn is nil, warnings are discarded.
+	Warn func(err errors.Error)
+
+	// Annotate, if non-nil, is called for each loaded instance, including its
+	// dependencies, before it is returned by Instances. It can be used to
+	// associate additional data with an instance using SetMeta.
+	Annotate func(inst *build.Instance)
+
 	fileSystem
 
 	loadFunc build.LoadFunc
 }
 
+func (c *Config) warn(err errors.Error) {
+	if c.Warn != nil {
+		c.Warn(err)
+	}
+}
+
 func (c *Config) stdin() io.Reader {
 	if c.Stdin == nil {
 		return os.Stdin
@@ -296,8 +351,7 @@ func (c *Config) newInstance(pos token.Pos, p importPath) *build.Instance {
 	i.PkgName = name
 	i.DisplayPath = string(p)
 	i.ImportPath = string(p)
-	i.Root = c.ModuleRoot
-	i.Module = c.Module
+	i.Root, i.Module = c.moduleFor(p)
 	i.Err = errors.Append(i.Err, err)
 
 	return i
@@ -463,13 +517,14 @@ func (c *Config) absDirFromImportPath(pos token.Pos, p importPath) (absDir, name
 
 	// Determine the directory.
 
+	root, module := c.moduleFor(p)
 	sub := filepath.FromSlash(string(p))
-	switch hasPrefix := strings.HasPrefix(string(p), c.Module); {
-	case hasPrefix && len(sub) == len(c.Module):
-		absDir = c.ModuleRoot
+	switch hasPrefix := strings.HasPrefix(string(p), module); {
+	case hasPrefix && len(sub) == len(module):
+		absDir = root
 
-	case hasPrefix && p[len(c.Module)] == '/':
-		absDir = filepath.Join(c.ModuleRoot, sub[len(c.Module)+1:])
+	case hasPrefix && p[len(module)] == '/':
+		absDir = filepath.Join(root, sub[len(module)+1:])
 
 	default:
 		absDir = filepath.Join(GenPath(c.ModuleRoot), sub)
@@ -525,50 +580,28 @@ func (c Config) complete() (cfg *Config, err error) {
 	}
 
 	// TODO: also make this work if run from outside the module?
-	switch {
-	case true:
-		mod := filepath.Join(c.ModuleRoot, modDir)
-		info, cerr := c.fileSystem.stat(mod)
-		if cerr != nil {
-			break
-		}
-		if info.IsDir() {
-			mod = filepath.Join(mod, configFile)
-		}
-		f, cerr := c.fileSystem.openFile(mod)
-		if cerr != nil {
-			break
-		}
-
-		// TODO: move to full build again
-		file, err := parser.ParseFile("load", f)
-		if err != nil {
-			return nil, errors.Wrapf(err, token.NoPos, "invalid cue.mod file")
-		}
-
-		r := runtime.New()
-		v, err := compile.Files(nil, r, "_", file)
+	ctx, v, err := c.readModuleFile(c.ModuleRoot)
+	if err != nil {
+		return nil, err
+	}
+	if v != nil {
+		name, pos, err := modulePath(ctx, v)
 		if err != nil {
-			return nil, errors.Wrapf(err, token.NoPos, "invalid cue.mod file")
+			return &c, err
 		}
-		ctx := eval.NewContext(r, v)
-		v.Finalize(ctx)
-		prefix := v.Lookup(ctx.StringLabel("module"))
-		if prefix != nil {
-			name := ctx.StringValue(prefix.Value())
-			if err := ctx.Err(); err != nil {
-				return &c, err.Err
-			}
-			pos := token.NoPos
-			src := prefix.Value().Source()
-			if src != nil {
-				pos = src.Pos()
-			}
+		if name != "" {
 			if c.Module != "" && c.Module != name {
 				return &c, errors.Newf(pos, "inconsistent modules: got %q, want %q", name, c.Module)
 			}
 			c.Module = name
 		}
+		c.checkLanguageVersion(ctx, v)
+	}
+
+	if c.Workspace != "" {
+		if err := c.loadWorkspace(); err != nil {
+			return &c, err
+		}
 	}
 
 	c.loadFunc = c.loader.loadFunc()
@@ -583,6 +616,111 @@ func (c Config) complete() (cfg *Config, err error) {
 	return &c, nil
 }
 
+// readModuleFile parses and evaluates the module file of the module rooted
+// at root. It returns a nil vertex if there is no such file.
+func (c *Config) readModuleFile(root string) (*adt.OpContext, *adt.Vertex, errors.Error) {
+	mod := filepath.Join(root, modDir)
+	info, err := c.fileSystem.stat(mod)
+	if err != nil {
+		return nil, nil, nil
+	}
+	if info.IsDir() {
+		mod = filepath.Join(mod, configFile)
+	}
+	f, err := c.fileSystem.openFile(mod)
+	if err != nil {
+		return nil, nil, nil
+	}
+	defer f.Close()
+
+	ctx, v, perr := evalConfigFile("load", f)
+	if perr != nil {
+		return nil, nil, errors.Wrapf(perr, token.NoPos, "invalid cue.mod file")
+	}
+	return ctx, v, nil
+}
+
+// evalConfigFile parses and evaluates a configuration file, such as a module
+// or workspace file.
+func evalConfigFile(filename string, src io.Reader) (*adt.OpContext, *adt.Vertex, error) {
+	// TODO: move to full build again
+	file, err := parser.ParseFile(filename, src)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r := runtime.New()
+	v, err := compile.Files(nil, r, "_", file)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx := eval.NewContext(r, v)
+	v.Finalize(ctx)
+	return ctx, v, nil
+}
+
+// modulePath returns the module field of the module file v, or "" if it is
+// not present.
+func modulePath(ctx *adt.OpContext, v *adt.Vertex) (string, token.Pos, errors.Error) {
+	prefix := v.Lookup(ctx.StringLabel("module"))
+	if prefix == nil {
+		return "", token.NoPos, nil
+	}
+	name := ctx.StringValue(prefix.Value())
+	if err := ctx.Err(); err != nil {
+		return "", token.NoPos, err.Err
+	}
+	pos := token.NoPos
+	if src := prefix.Value().Source(); src != nil {
+		pos = src.Pos()
+	}
+	return name, pos, nil
+}
+
+// checkLanguageVersion verifies the language.version field of the module file
+// v, if present, and warns if it is invalid or newer than the supported
+// language version.
+func (c *Config) checkLanguageVersion(ctx *adt.OpContext, v *adt.Vertex) {
+	s, pos, err := languageVersion(ctx, v)
+	if err != nil {
+		c.warn(err)
+		return
+	}
+	if s == "" {
+		return
+	}
+	if current := internal.LanguageVersion(); semver.Compare(s, current) > 0 {
+		c.warn(errors.Newf(pos,
+			"module requires language version %s, but the current version is %s",
+			s, current))
+	}
+}
+
+// languageVersion returns the language.version field of the module file v,
+// or "" if it is not present.
+func languageVersion(ctx *adt.OpContext, v *adt.Vertex) (string, token.Pos, errors.Error) {
+	lang := v.Lookup(ctx.StringLabel("language"))
+	if lang == nil {
+		return "", token.NoPos, nil
+	}
+	version := lang.Lookup(ctx.StringLabel("version"))
+	if version == nil {
+		return "", token.NoPos, nil
+	}
+	s := ctx.StringValue(version.Value())
+	if err := ctx.Err(); err != nil {
+		return "", token.NoPos, err.Err
+	}
+	pos := token.NoPos
+	if src := version.Value().Source(); src != nil {
+		pos = src.Pos()
+	}
+	if !semver.IsValid(s) {
+		return "", pos, errors.Newf(pos, "invalid language version %q", s)
+	}
+	return s, pos, nil
+}
+
 func (c Config) isRoot(dir string) bool {
 	fs := &c.fileSystem
 	// Note: cue.mod used to be a file. We still allow both to match.
@@ -79,6 +79,7 @@ func (l *loader) importPkg(pos token.Pos, p *build.Instance) []*build.Instance {
 
 	cfg := l.cfg
 	ctxt := &cfg.fileSystem
+	root := p.Root // the root of the module providing p
 
 	if p.Err != nil {
 		return []*build.Instance{p}
@@ -92,7 +93,7 @@ func (l *loader) importPkg(pos token.Pos, p *build.Instance) []*build.Instance {
 		return []*build.Instance{p}
 	}
 
-	if !strings.HasPrefix(p.Dir, cfg.ModuleRoot) {
+	if !strings.HasPrefix(p.Dir, root) {
 		err := errors.Newf(token.NoPos, "module root not defined", p.DisplayPath)
 		return retErr(err)
 	}
@@ -113,12 +114,12 @@ func (l *loader) importPkg(pos token.Pos, p *build.Instance) []*build.Instance {
 		fp.ignoreOther = true
 	}
 
-	if !strings.HasPrefix(p.Dir, cfg.ModuleRoot) {
+	if !strings.HasPrefix(p.Dir, root) {
 		panic("")
 	}
 
 	var dirs [][2]string
-	genDir := GenPath(cfg.ModuleRoot)
+	genDir := GenPath(root)
 	if strings.HasPrefix(p.Dir, genDir) {
 		dirs = append(dirs, [2]string{genDir, p.Dir})
 		// TODO(legacy): don't support "pkg"
@@ -131,13 +132,13 @@ func (l *loader) importPkg(pos token.Pos, p *build.Instance) []*build.Instance {
 					return retErr(
 						errors.Wrapf(err, token.NoPos, "invalid path"))
 				}
-				base := filepath.Join(cfg.ModuleRoot, modDir, sub)
+				base := filepath.Join(root, modDir, sub)
 				dir := filepath.Join(base, rel)
 				dirs = append(dirs, [2]string{base, dir})
 			}
 		}
 	} else {
-		dirs = append(dirs, [2]string{cfg.ModuleRoot, p.Dir})
+		dirs = append(dirs, [2]string{root, p.Dir})
 	}
 
 	found := false
@@ -221,13 +222,13 @@ func (l *loader) importPkg(pos token.Pos, p *build.Instance) []*build.Instance {
 		}
 
 		all = append(all, p)
-		rewriteFiles(p, cfg.ModuleRoot, false)
+		rewriteFiles(p, root, false)
 		if errs := fp.finalize(p); errs != nil {
 			p.ReportError(errs)
 			return all
 		}
 
-		l.addFiles(cfg.ModuleRoot, p)
+		l.addFiles(root, p)
 		_ = p.Complete()
 	}
 	sort.Slice(all, func(i, j int) bool {
@@ -394,6 +395,15 @@ func (fp *fileProcessor) add(pos token.Pos, root string, file *build.File, mode
 		return false
 	}
 
+	if fullPath == "-" && fp.c.StdinFilename != "" {
+		fullPath = fp.c.StdinFilename
+		if !filepath.IsAbs(fullPath) {
+			fullPath = filepath.Join(root, fullPath)
+		}
+		file.Filename = fullPath
+		base = filepath.Base(fullPath)
+	}
+
 	pf, perr := parser.ParseFile(fullPath, data, parser.ImportsOnly, parser.ParseComments)
 	if perr != nil {
 		badFile(errors.Promote(perr, "add failed"))
@@ -47,6 +47,33 @@ func Instances(args []string, c *Config) []*build.Instance {
 	if c == nil {
 		c = &Config{}
 	}
+	a := instances(args, c)
+	if c.Annotate != nil {
+		annotate(a, c.Annotate)
+	}
+	return a
+}
+
+// annotate calls f for each instance in a and its dependencies.
+func annotate(a []*build.Instance, f func(inst *build.Instance)) {
+	seen := map[*build.Instance]bool{}
+	var visit func(p *build.Instance)
+	visit = func(p *build.Instance) {
+		if seen[p] {
+			return
+		}
+		seen[p] = true
+		f(p)
+		for _, d := range p.Imports {
+			visit(d)
+		}
+	}
+	for _, p := range a {
+		visit(p)
+	}
+}
+
+func instances(args []string, c *Config) []*build.Instance {
 	newC, err := c.complete()
 	if err != nil {
 		return []*build.Instance{c.newErrInstance(token.NoPos, "", err)}
@@ -16,6 +16,8 @@ package load
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -27,7 +29,10 @@ import (
 	"github.com/kylelemons/godebug/diff"
 
 	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/build"
+	cueerrors "cuelang.org/go/cue/errors"
 	"cuelang.org/go/cue/format"
+	"cuelang.org/go/internal"
 	"cuelang.org/go/internal/str"
 )
 
@@ -401,3 +406,311 @@ func TestOverlays(t *testing.T) {
 		}
 	}
 }
+
+func TestStdin(t *testing.T) {
+	cwd, _ := os.Getwd()
+	abs := func(path string) string {
+		return filepath.Join(cwd, path)
+	}
+	load := func(src string) *build.Instance {
+		c := &Config{
+			Dir: abs("dir"),
+			Overlay: map[string]Source{
+				abs("cue.mod"): FromString(`module: "mod.test"`),
+				abs("dir/foo/foo.cue"): FromString(`
+				   package foo
+
+				   a: 5
+				`),
+			},
+			Stdin:         strings.NewReader(src),
+			StdinFilename: "stdin.cue",
+		}
+		return Instances([]string{"-"}, c)[0]
+	}
+
+	inst := load(`
+		package top
+
+		import "mod.test/dir/foo"
+
+		b: foo.a
+	`)
+	if inst.Err != nil {
+		t.Fatal(inst.Err)
+	}
+	if got, want := inst.BuildFiles[0].Filename, abs("dir/stdin.cue"); got != want {
+		t.Errorf("got filename %q; want %q", got, want)
+	}
+	v := cue.Build([]*build.Instance{inst})[0].Value()
+	b, err := format.Node(v.Syntax(cue.Final()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.Join(strings.Fields(string(b)), ""), `{b:5}`; got != want {
+		t.Errorf("got %s; want %s", got, want)
+	}
+
+	inst = load(`
+		package top
+
+		b: 1 +
+	`)
+	want := abs("dir/stdin.cue") + ":5:2"
+	if err := inst.Err; err == nil || !strings.Contains(cueerrors.Details(err, nil), want) {
+		t.Errorf("got error %v; want position %s", err, want)
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	type revKey struct{}
+
+	cwd, _ := os.Getwd()
+	c := &Config{
+		Dir: filepath.Join(cwd, "testdata"),
+		Annotate: func(inst *build.Instance) {
+			inst.SetMeta(revKey{}, "rev:"+inst.ImportPath)
+		},
+	}
+	insts := Instances([]string{"./imports"}, c)
+	inst := insts[0]
+	if inst.Err != nil {
+		t.Fatal(inst.Err)
+	}
+	if got, want := inst.Meta(revKey{}), "rev:"+inst.ImportPath; got != want {
+		t.Errorf("got %v; want %v", got, want)
+	}
+	if len(inst.Imports) == 0 {
+		t.Fatal("expected imports")
+	}
+	for _, imp := range inst.Imports {
+		if got, want := imp.Meta(revKey{}), "rev:"+imp.ImportPath; got != want {
+			t.Errorf("import %s: got %v; want %v", imp.ImportPath, got, want)
+		}
+	}
+	if got := inst.Meta(struct{}{}); got != nil {
+		t.Errorf("got %v for unknown key; want nil", got)
+	}
+
+	built := cue.Build(insts)[0]
+	if got, want := built.Meta(revKey{}), "rev:"+inst.ImportPath; got != want {
+		t.Errorf("built instance: got %v; want %v", got, want)
+	}
+}
+
+func TestPreprocess(t *testing.T) {
+	cwd, _ := os.Getwd()
+	abs := func(path string) string {
+		return filepath.Join(cwd, path)
+	}
+	// The macro @@msg@@ expands to a field; @@import@@ expands to an import
+	// so that the imports seen by the loader must also be preprocessed.
+	expand := func(path string, src []byte) ([]byte, error) {
+		if bytes.Contains(src, []byte("@@fail@@")) {
+			return nil, errors.New("cannot expand macro")
+		}
+		src = bytes.ReplaceAll(src, []byte("@@import@@"), []byte(`import "strings"`))
+		src = bytes.ReplaceAll(src, []byte("@@msg@@"), []byte(`msg: strings.ToUpper("hello")`))
+		return src, nil
+	}
+	c := &Config{
+		Overlay: map[string]Source{
+			abs("cue.mod"): FromString(`module: "mod.test"`),
+
+			abs("dir/top.cue"): FromString(`
+			   package top
+
+			   @@import@@
+
+			   @@msg@@
+			`),
+			abs("bad/bad.cue"): FromString(`
+			   package bad
+
+			   @@fail@@
+			`),
+		},
+		Preprocess: expand,
+	}
+
+	insts := cue.Build(Instances([]string{"./dir"}, c))
+	if err := insts[0].Err; err != nil {
+		t.Fatal(err)
+	}
+	b, err := format.Node(insts[0].Value().Syntax(cue.Final()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.Join(strings.Fields(string(b)), ""), `{msg:"HELLO"}`; got != want {
+		t.Errorf("got %s; want %s", got, want)
+	}
+
+	err = Instances([]string{"./bad"}, c)[0].Err
+	want := "preprocess " + abs("bad/bad.cue") + ": cannot expand macro"
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("got error %v; want %q", err, want)
+	}
+}
+
+func TestLanguageVersion(t *testing.T) {
+	cwd, _ := os.Getwd()
+	run := func(version string) (warnings []string, err error) {
+		c := &Config{
+			Overlay: map[string]Source{
+				filepath.Join(cwd, "cue.mod"): FromString(fmt.Sprintf(`
+				module: "mod.test"
+				language: version: %q
+				`, version)),
+				filepath.Join(cwd, "dir/top.cue"): FromString(`
+				package top
+
+				a: 1
+				`),
+			},
+			Warn: func(err cueerrors.Error) {
+				warnings = append(warnings, err.Error())
+			},
+		}
+		return warnings, Instances([]string{"./dir"}, c)[0].Err
+	}
+
+	warnings, err := run(internal.LanguageVersion())
+	if err != nil || len(warnings) > 0 {
+		t.Errorf("current version: got warnings %v and error %v", warnings, err)
+	}
+
+	warnings, err = run("v99.0.0")
+	want := "module requires language version v99.0.0, but the current version is " +
+		internal.LanguageVersion()
+	if err != nil || len(warnings) != 1 || warnings[0] != want {
+		t.Errorf("newer version: got warnings %q and error %v; want %q", warnings, err, want)
+	}
+
+	warnings, err = run("0.4")
+	want = `invalid language version "0.4"`
+	if err != nil || len(warnings) != 1 || warnings[0] != want {
+		t.Errorf("invalid version: got warnings %q and error %v; want %q", warnings, err, want)
+	}
+}
+
+func TestWorkspace(t *testing.T) {
+	cwd, _ := os.Getwd()
+	abs := func(path string) string {
+		return filepath.Join(cwd, "ws", path)
+	}
+	files := func(work, appMod, libMod string) map[string]Source {
+		return map[string]Source{
+			abs("cue.work"): FromString(work),
+
+			abs("app/cue.mod"): FromString(appMod),
+			abs("app/main.cue"): FromString(`
+			package main
+
+			import "lib.test/schema"
+
+			x: schema.#Def & {a: 1}
+			`),
+			abs("app/other/other.cue"): FromString(`
+			package other
+
+			import "other.test/o"
+
+			y: o.y
+			`),
+
+			abs("lib/cue.mod"): FromString(libMod),
+			abs("lib/schema/schema.cue"): FromString(`
+			package schema
+
+			import "lib.test/util"
+
+			#Def: {
+				a: int
+				b: util.two
+			}
+			`),
+			abs("lib/util/util.cue"): FromString(`
+			package util
+
+			two: 2
+			`),
+
+			abs("other/cue.mod"): FromString(`module: "other.test"`),
+			abs("other/o/o.cue"): FromString(`
+			package o
+
+			y: 3
+			`),
+		}
+	}
+	const (
+		work   = `modules: ["./app", "./lib"]`
+		appMod = `module: "app.test", language: version: "v0.4.3"`
+		libMod = `module: "lib.test"`
+	)
+
+	testCases := []struct {
+		name    string
+		overlay map[string]Source
+		pkg     string
+		want    string
+		err     string
+	}{{
+		name:    "import across modules",
+		overlay: files(work, appMod, libMod),
+		pkg:     ".",
+		want:    `{ x: { a: 1 b: 2 } }`,
+	}, {
+		name:    "load package of member module",
+		overlay: files(work, appMod, libMod),
+		pkg:     "lib.test/util",
+		want:    `{ two: 2 }`,
+	}, {
+		name:    "unlisted module",
+		overlay: files(work, appMod, libMod),
+		pkg:     "./other",
+		err:     `cannot find package "other.test/o"`,
+	}, {
+		name:    "duplicate module",
+		overlay: files(work, appMod, `module: "app.test"`),
+		pkg:     ".",
+		err:     "module app.test is provided by both",
+	}, {
+		name:    "conflicting versions",
+		overlay: files(work, appMod, `module: "lib.test", language: version: "v0.4.0"`),
+		pkg:     ".",
+		err:     "conflicting language versions: app.test requires v0.4.3, but lib.test requires v0.4.0",
+	}, {
+		name:    "missing module path",
+		overlay: files(work, appMod, `language: version: "v0.4.3"`),
+		pkg:     ".",
+		err:     "workspace module ./lib: module path not declared",
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &Config{
+				Dir:       abs("app"),
+				Workspace: "../cue.work",
+				Overlay:   tc.overlay,
+			}
+			inst := cue.Build(Instances([]string{tc.pkg}, c))[0]
+			if tc.err != "" {
+				if inst.Err == nil || !strings.Contains(inst.Err.Error(), tc.err) {
+					t.Fatalf("got error %v; want %q", inst.Err, tc.err)
+				}
+				return
+			}
+			if inst.Err != nil {
+				t.Fatal(inst.Err)
+			}
+			b, err := format.Node(inst.Value().Syntax(cue.Final()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := strings.Join(strings.Fields(string(b)), " ")
+			if got != tc.want {
+				t.Errorf("got %s; want %s", got, tc.want)
+			}
+		})
+	}
+}
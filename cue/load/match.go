@@ -19,6 +19,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"cuelang.org/go/cue/ast"
 	"cuelang.org/go/cue/build"
 	"cuelang.org/go/cue/errors"
 	"cuelang.org/go/cue/token"
@@ -60,6 +61,11 @@ func matchFile(cfg *Config, file *build.File, returnImports, allFiles bool, allT
 			err = errors.Newf(token.NoPos, "read stdin: %v", err)
 			return
 		}
+		if cfg.Preprocess != nil {
+			if b, err = preprocess(cfg, file, b); err != nil {
+				return false, nil, err
+			}
+		}
 		file.Source = b
 		return true, b, nil // don't check shouldBuild for stdin
 	}
@@ -82,6 +88,22 @@ func matchFile(cfg *Config, file *build.File, returnImports, allFiles bool, allT
 		return false, nil, err
 	}
 
+	if _, ok := file.Source.(*ast.File); cfg.Preprocess != nil && !ok {
+		b, err2 := ioutil.ReadAll(f)
+		f.Close()
+		if err2 != nil {
+			return false, nil,
+				errors.Newf(token.NoPos, "read %s: %v", file.Filename, err2)
+		}
+		// Record the result as the source of the file so that it is also
+		// used when the file is parsed in full.
+		if data, err = preprocess(cfg, file, b); err != nil {
+			return false, nil, err
+		}
+		file.Source = data
+		return true, data, nil
+	}
+
 	data, err = readImports(f, false, nil)
 	f.Close()
 	if err != nil {
@@ -91,3 +113,12 @@ func matchFile(cfg *Config, file *build.File, returnImports, allFiles bool, allT
 
 	return true, data, nil
 }
+
+// preprocess applies cfg.Preprocess to the contents src of file.
+func preprocess(cfg *Config, file *build.File, src []byte) ([]byte, errors.Error) {
+	b, err := cfg.Preprocess(file.Filename, src)
+	if err != nil {
+		return nil, errors.Wrapf(err, token.NoPos, "preprocess %s", file.Filename)
+	}
+	return b, nil
+}
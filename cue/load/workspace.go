@@ -0,0 +1,169 @@
+// Copyright 2022 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package load
+
+import (
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/semver"
+
+	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/token"
+)
+
+// A workspaceModule is a module listed in a workspace file.
+type workspaceModule struct {
+	root    string // absolute root directory
+	module  string // module path
+	version string // required language version, if any
+}
+
+// loadWorkspace reads the workspace file c.Workspace and records the modules
+// it lists in c.workspace.
+func (c *Config) loadWorkspace() errors.Error {
+	file := c.Workspace
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(c.Dir, file)
+	}
+	f, err := c.fileSystem.openFile(file)
+	if err != nil {
+		return errors.Wrapf(err, token.NoPos, "cannot read workspace file")
+	}
+	defer f.Close()
+
+	ctx, v, perr := evalConfigFile(file, f)
+	if perr != nil {
+		return errors.Wrapf(perr, token.NoPos, "invalid workspace file")
+	}
+
+	modules := v.Lookup(ctx.StringLabel("modules"))
+	if modules == nil || !modules.IsList() {
+		return errors.Newf(token.NoPos,
+			"invalid workspace file %s: modules must be a list of directories", file)
+	}
+
+	// The main module always takes part in the workspace, even if it is not
+	// listed explicitly.
+	main := &workspaceModule{root: c.ModuleRoot, module: c.Module}
+	if mctx, mv, err := c.readModuleFile(c.ModuleRoot); err != nil {
+		return err
+	} else if mv != nil {
+		if main.version, _, err = languageVersion(mctx, mv); err != nil {
+			return err
+		}
+	}
+	all := []*workspaceModule{main}
+
+	for _, a := range modules.Elems() {
+		dir := ctx.StringValue(a.Value())
+		if err := ctx.Err(); err != nil {
+			return errors.Wrapf(err.Err, token.NoPos, "invalid workspace file %s", file)
+		}
+		root := filepath.Join(filepath.Dir(file), filepath.FromSlash(dir))
+		if root == c.ModuleRoot {
+			continue
+		}
+		m, err := c.readWorkspaceModule(dir, root)
+		if err != nil {
+			return err
+		}
+		all = append(all, m)
+	}
+
+	var version *workspaceModule
+	paths := map[string]*workspaceModule{}
+	for _, m := range all {
+		if m.module != "" {
+			if x, ok := paths[m.module]; ok {
+				return errors.Newf(token.NoPos,
+					"module %s is provided by both %s and %s",
+					m.module, x.root, m.root)
+			}
+			paths[m.module] = m
+		}
+		if m.version == "" {
+			continue
+		}
+		if version == nil {
+			version = m
+		} else if semver.Compare(m.version, version.version) != 0 {
+			return errors.Newf(token.NoPos,
+				"conflicting language versions: %s requires %s, but %s requires %s",
+				version.displayName(), version.version, m.displayName(), m.version)
+		}
+	}
+
+	c.workspace = all[1:]
+	return nil
+}
+
+// readWorkspaceModule reads the module file of the workspace module at root,
+// which is listed as dir in the workspace file.
+func (c *Config) readWorkspaceModule(dir, root string) (*workspaceModule, errors.Error) {
+	ctx, v, err := c.readModuleFile(root)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, errors.Newf(token.NoPos,
+			"workspace module %s: no %s found", dir, modDir)
+	}
+	m := &workspaceModule{root: root}
+	if m.module, _, err = modulePath(ctx, v); err != nil {
+		return nil, err
+	}
+	if m.module == "" {
+		return nil, errors.Newf(token.NoPos,
+			"workspace module %s: module path not declared", dir)
+	}
+	if m.version, _, err = languageVersion(ctx, v); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *workspaceModule) displayName() string {
+	if m.module == "" {
+		return m.root
+	}
+	return m.module
+}
+
+// moduleFor returns the root directory and module path of the module that
+// provides the package with import path p. This is the workspace module with
+// the longest module path that is a prefix of p or the main module otherwise.
+func (c *Config) moduleFor(p importPath) (root, module string) {
+	root, module = c.ModuleRoot, c.Module
+	n := -1
+	if c.Module != "" && hasModulePrefix(string(p), c.Module) {
+		n = len(c.Module)
+	}
+	for _, m := range c.workspace {
+		if len(m.module) > n && hasModulePrefix(string(p), m.module) {
+			root, module, n = m.root, m.module, len(m.module)
+		}
+	}
+	return root, module
+}
+
+// hasModulePrefix reports whether the import path p refers to a package
+// within the module with the given module path.
+func hasModulePrefix(p, module string) bool {
+	if !strings.HasPrefix(p, module) {
+		return false
+	}
+	return len(p) == len(module) || p[len(module)] == '/' || p[len(module)] == ':'
+}
@@ -0,0 +1,383 @@
+// Copyright 2022 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cue
+
+import (
+	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/token"
+	"cuelang.org/go/internal/core/adt"
+)
+
+// A ListMergeStrategy defines how two lists are combined by Unify.
+type ListMergeStrategy int
+
+const (
+	// ListStrict unifies lists element by element. Two closed lists of
+	// different lengths conflict. This is the default.
+	ListStrict ListMergeStrategy = iota
+
+	// ListOverride replaces the elements of the first list with those of the
+	// second list at the same index and keeps the remaining elements of the
+	// longer list. For instance, [1, 2] and [3] combine to [3, 2]. The
+	// element constraint of an open list, as in [...int], still applies to
+	// the elements beyond its length. The resulting list is open only if
+	// both lists are open.
+	ListOverride
+
+	// ListAppend combines two lists by appending the elements of the second
+	// list to those of the first. The resulting list is closed.
+	ListAppend
+)
+
+func (s ListMergeStrategy) String() string {
+	switch s {
+	case ListOverride:
+		return "override"
+	case ListAppend:
+		return "append"
+	default:
+		return "strict"
+	}
+}
+
+func parseListMergeStrategy(s string) (ListMergeStrategy, bool) {
+	switch s {
+	case "strict":
+		return ListStrict, true
+	case "override":
+		return ListOverride, true
+	case "append":
+		return ListAppend, true
+	}
+	return ListStrict, false
+}
+
+// listStrategy reports the list merge strategy for unifying v and w, taking
+// into account any @merge attributes associated with either value.
+func listStrategy(v, w Value, s ListMergeStrategy) (ListMergeStrategy, *adt.Bottom) {
+	found := false
+	for _, x := range []Value{v, w} {
+		a := x.Attribute("merge")
+		str, ok, err := a.Lookup(0, "list")
+		if err != nil || !ok {
+			continue
+		}
+		t, ok := parseListMergeStrategy(str)
+		if !ok {
+			return s, x.ctx().NewErrf(
+				"invalid list merge strategy %q in @merge attribute", str)
+		}
+		if found && t != s {
+			return s, x.ctx().NewErrf(
+				"conflicting list merge strategies %s and %s", s, t)
+		}
+		s, found = t, true
+	}
+	return s, nil
+}
+
+// unifyLists unifies v and w using strategy s for combining lists. It reports
+// whether the result differs from the result of regular unification.
+func unifyLists(v, w Value, s ListMergeStrategy) (Value, bool) {
+	s, err := listStrategy(v, w, s)
+	if err != nil {
+		return newErrValue(v, err), true
+	}
+
+	x, y := v.v, w.v
+	switch {
+	case x.IsList() && y.IsList():
+		switch s {
+		case ListAppend:
+			return appendLists(v, w), true
+
+		case ListOverride:
+			return overrideLists(v, w), true
+		}
+		return unifyArcs(v, w, s)
+
+	case x.Kind() == adt.StructKind && y.Kind() == adt.StructKind:
+		return unifyArcs(v, w, s)
+	}
+	return v.unify(w), false
+}
+
+// unifyArcs unifies v and w, applying strategy s to the arcs that v and w
+// have in common. Other than its arcs, the result is identical to that of
+// regular unification, so that, for instance, pattern constraints and
+// closedness are retained.
+func unifyArcs(v, w Value, s ListMergeStrategy) (Value, bool) {
+	u := v.unify(w)
+
+	changed := false
+	arcs := make([]*adt.Vertex, len(u.v.Arcs))
+	for i, a := range u.v.Arcs {
+		arcs[i] = a
+		x := v.v.Lookup(a.Label)
+		y := w.v.Lookup(a.Label)
+		if x == nil || y == nil {
+			continue
+		}
+		m, ok := unifyLists(makeChildValue(v, x), makeChildValue(w, y), s)
+		if ok {
+			arcs[i] = m.v
+			changed = true
+		}
+	}
+	if !changed {
+		return u, false
+	}
+
+	n := *u.v
+	n.Arcs = arcs
+
+	// Recompute the errors of the replaced arcs.
+	n.ChildErrors = nil
+	if b, ok := n.BaseValue.(*adt.Bottom); ok && b.ChildError {
+		if n.IsList() {
+			n.BaseValue = &adt.ListMarker{
+				IsOpen: !v.v.IsClosedList() && !w.v.IsClosedList(),
+			}
+		} else {
+			n.BaseValue = &adt.StructMarker{}
+		}
+	}
+	for _, a := range arcs {
+		if b, ok := a.BaseValue.(*adt.Bottom); ok {
+			n.AddChildError(b)
+		}
+	}
+
+	return makeValue(u.idx, &n, u.parent_), true
+}
+
+// overrideElems returns the elements of list w followed by the elements of
+// list v beyond the length of w.
+func overrideElems(v, w Value) []adt.Elem {
+	xa, ya := v.v.Elems(), w.v.Elems()
+
+	elems := make([]adt.Elem, 0, len(xa)+len(ya))
+	for _, e := range ya {
+		elems = append(elems, e)
+	}
+	for i := len(ya); i < len(xa); i++ {
+		elems = append(elems, xa[i])
+	}
+	return elems
+}
+
+// overrideLists returns the elements of list w followed by the remaining
+// elements of list v, constrained by the element constraints of both lists.
+func overrideLists(v, w Value) Value {
+	list := &adt.ListLit{Elems: overrideElems(v, w)}
+	if !v.v.IsClosedList() && !w.v.IsClosedList() {
+		list.Elems = append(list.Elems, &adt.Ellipsis{})
+	}
+
+	n := &adt.Vertex{
+		Parent: v.v.Parent,
+		Label:  v.v.Label,
+	}
+	n.AddConjunct(adt.MakeRootConjunct(nil, list))
+	addElementConstraints(n, v.v)
+	addElementConstraints(n, w.v)
+	n.Finalize(v.ctx())
+	n.Closed = v.v.Closed || w.v.Closed
+
+	return makeValue(v.idx, n, v.parent_)
+}
+
+// addElementConstraints adds the element constraints of list x, such as int
+// for [1, ...int], to n. The constraints apply to the elements of n beyond the
+// length of x.
+func addElementConstraints(n, x *adt.Vertex) {
+	k := len(x.Elems())
+	for _, s := range x.Structs {
+		for _, d := range s.Decls {
+			e, ok := d.(*adt.Ellipsis)
+			if !ok {
+				continue
+			}
+			elems := make([]adt.Elem, k, k+1)
+			for i := range elems {
+				elems[i] = &adt.Top{}
+			}
+			list := &adt.ListLit{Elems: append(elems, e)}
+			n.AddConjunct(adt.MakeRootConjunct(s.Env, list))
+		}
+	}
+}
+
+// appendLists returns a closed list with the elements of w appended to those
+// of v.
+func appendLists(v, w Value) Value {
+	var elems []adt.Elem
+	for _, e := range v.v.Elems() {
+		elems = append(elems, e)
+	}
+	for _, e := range w.v.Elems() {
+		elems = append(elems, e)
+	}
+	return newMergedValue(v, w, &adt.ListLit{Elems: elems})
+}
+
+// newMergedValue evaluates x, along with any additional conjuncts, in place
+// of the unification of v and w.
+func newMergedValue(v, w Value, x adt.Expr, extra ...adt.Conjunct) Value {
+	n := &adt.Vertex{
+		Parent: v.v.Parent,
+		Label:  v.v.Label,
+	}
+	n.AddConjunct(adt.MakeRootConjunct(nil, x))
+	for _, c := range extra {
+		n.AddConjunct(c)
+	}
+	n.Finalize(v.ctx())
+	n.Closed = v.v.Closed || w.v.Closed
+
+	return makeValue(v.idx, n, v.parent_)
+}
+
+// Overlay merges the given layers in order, where later layers override
+// earlier ones. Unlike Unify, which is the only operation that follows the
+// semantics of the CUE language, Overlay does not fail if two layers define
+// conflicting values. Instead:
+//
+//   - values that unify without error are unified, so that, for instance,
+//     int and 3 merge to 3;
+//   - two structs are merged field by field, recursively, and the pattern
+//     constraints and optional fields of both apply to the result;
+//   - in case of any other conflict, the value of the later layer wins.
+//
+// Two lists are merged according to a ListMergeStrategy, which may be set for
+// a field with a @merge(list=<strategy>) attribute in either layer. With
+// ListStrict, the default, lists of the same length are merged element by
+// element and a list of a different length replaces the earlier one. With
+// ListOverride, the elements of the later list replace those of the earlier
+// list by index and the remaining elements of the longer list are retained.
+// With ListAppend, the elements of the later
+// list are appended to those of the earlier one.
+//
+// Overlay reports an error if any of the layers is an error or if the result is
+// an error.
+func Overlay(layers ...Value) (Value, error) {
+	if len(layers) == 0 {
+		return Value{}, errors.Newf(token.NoPos, "no layers to overlay")
+	}
+	v := layers[0]
+	if err := v.Err(); err != nil {
+		return v, err
+	}
+	for _, w := range layers[1:] {
+		if err := w.Err(); err != nil {
+			return w, err
+		}
+		v = overlayValue(v, w)
+		if err := v.Err(); err != nil {
+			return v, err
+		}
+	}
+	return v, nil
+}
+
+// overlayValue merges w onto v as described for Overlay.
+func overlayValue(v, w Value) Value {
+	x, y := v.v, w.v
+	switch {
+	case x.IsList() && y.IsList():
+		s, err := listStrategy(v, w, ListStrict)
+		if err != nil {
+			return newErrValue(v, err)
+		}
+		return overlayLists(v, w, s)
+
+	case x.Kind() == adt.StructKind && y.Kind() == adt.StructKind:
+		return overlayStructs(v, w)
+	}
+	if u := v.Unify(w); u.Err() == nil {
+		return u
+	}
+	return w
+}
+
+// overlayStructs merges the fields of w onto those of v. The pattern
+// constraints and optional fields of both structs apply to the result.
+func overlayStructs(v, w Value) Value {
+	st := &adt.StructLit{}
+	add := func(a *adt.Vertex) {
+		st.Decls = append(st.Decls, &adt.Field{Label: a.Label, Value: a})
+	}
+	for _, a := range v.v.Arcs {
+		if b := w.v.Lookup(a.Label); b != nil {
+			a = overlayValue(makeChildValue(v, a), makeChildValue(w, b)).v
+		}
+		add(a)
+	}
+	for _, b := range w.v.Arcs {
+		if v.v.Lookup(b.Label) == nil {
+			add(b)
+		}
+	}
+	constraints := append(structConstraints(v.v), structConstraints(w.v)...)
+	return newMergedValue(v, w, st, constraints...)
+}
+
+// structConstraints returns the pattern constraints and optional fields of
+// struct x, which are not represented by its arcs.
+func structConstraints(x *adt.Vertex) []adt.Conjunct {
+	var a []adt.Conjunct
+	for _, s := range x.Structs {
+		var decls []adt.Decl
+		for _, d := range s.Decls {
+			switch d.(type) {
+			case *adt.OptionalField, *adt.BulkOptionalField:
+				decls = append(decls, d)
+			}
+		}
+		if decls != nil {
+			st := &adt.StructLit{Decls: decls}
+			a = append(a, adt.MakeRootConjunct(s.Env, st))
+		}
+	}
+	return a
+}
+
+// overlayLists merges list w onto list v using strategy s.
+func overlayLists(v, w Value, s ListMergeStrategy) Value {
+	xa, ya := v.v.Elems(), w.v.Elems()
+	switch {
+	case s == ListAppend:
+		return appendLists(v, w)
+
+	case s == ListStrict && len(xa) != len(ya):
+		return w
+
+	case s == ListOverride:
+		return newMergedValue(v, w, &adt.ListLit{Elems: overrideElems(v, w)})
+	}
+
+	long := xa
+	if len(ya) > len(xa) {
+		long = ya
+	}
+	elems := make([]adt.Elem, len(long))
+	for i, e := range long {
+		if i < len(xa) && i < len(ya) {
+			e = overlayValue(makeChildValue(v, xa[i]), makeChildValue(w, ya[i])).v
+		}
+		elems[i] = e
+	}
+	return newMergedValue(v, w, &adt.ListLit{Elems: elems})
+}
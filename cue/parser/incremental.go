@@ -0,0 +1,246 @@
+// Copyright 2022 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"bytes"
+
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/ast/astutil"
+	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/token"
+)
+
+// An Edit describes a change to a source file: the bytes in the range
+// [Start, End) are replaced with Text.
+type Edit struct {
+	Start, End int
+	Text       []byte
+}
+
+// ReparseFile parses the source obtained by applying edit to src. The file f
+// must be the result of successfully parsing src with ParseFile using the
+// same options as passed to ReparseFile.
+//
+// If the edit is confined to a single top-level declaration, only that
+// declaration is parsed again and the other declarations of f are reused.
+// Otherwise, or if this cannot be done safely, the entire source is parsed
+// again. Either way, the result is the same as that of calling ParseFile on
+// the edited source.
+//
+// Only scanning and parsing are limited to the edited declaration. The
+// positions of all other nodes still need to be moved to a new file and all
+// identifiers resolved again, so the cost of ReparseFile remains linear in the
+// size of the file, and the speedup over ParseFile is modest.
+//
+// ReparseFile may modify f and reuse its nodes; f must not be used after
+// calling ReparseFile.
+func ReparseFile(f *ast.File, src []byte, edit Edit, mode ...Option) (*ast.File, error) {
+	if edit.Start < 0 || edit.Start > edit.End || edit.End > len(src) {
+		return nil, errors.Newf(token.NoPos,
+			"invalid edit range [%d, %d) for source of length %d",
+			edit.Start, edit.End, len(src))
+	}
+	text := make([]byte, 0, len(src)-(edit.End-edit.Start)+len(edit.Text))
+	text = append(text, src[:edit.Start]...)
+	text = append(text, edit.Text...)
+	text = append(text, src[edit.End:]...)
+
+	if reparseDecl(f, src, text, edit, mode) {
+		return f, nil
+	}
+	return ParseFile(f.Filename, text, mode...)
+}
+
+// reparseDecl updates f for the edited source text by parsing only the
+// top-level declaration containing the edit. It reports whether it succeeded;
+// f is not modified if it did not.
+func reparseDecl(f *ast.File, src, text []byte, edit Edit, mode []Option) bool {
+	// Line directives are recorded in the file while scanning comments and
+	// would be lost when rebuilding the line table.
+	if bytes.Contains(src, []byte("//line ")) {
+		return false
+	}
+
+	// Find the declaration that strictly contains the edit. The text before
+	// the edited declaration must remain unchanged to ensure that its first
+	// token is preceded by the same whitespace. Edits at the end of the
+	// declaration are only allowed if a newline follows and none is inserted,
+	// so that the edit cannot merge with subsequent tokens or change the
+	// spacing of the next declaration.
+	index := -1
+	var start, end int
+	for i, d := range f.Decls {
+		if d.Pos().File() == nil || d.End().File() == nil {
+			return false
+		}
+		start, end = d.Pos().Offset(), d.End().Offset()
+		if start < edit.Start && edit.End <= end {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return false
+	}
+	if edit.End == end && (end < len(src) && src[end] != '\n' ||
+		bytes.IndexByte(edit.Text, '\n') >= 0) {
+		return false
+	}
+
+	old := f.Decls[index]
+	if !isReparsable(old) || len(ast.Comments(old)) > 0 {
+		return false
+	}
+	delta := len(text) - len(src)
+	region := text[start : end+delta]
+	if bytes.Contains(region, []byte("//")) {
+		return false
+	}
+
+	rf, err := ParseFile(f.Filename, region, mode...)
+	if err != nil || len(rf.Decls) != 1 || !isReparsable(rf.Decls[0]) {
+		return false
+	}
+	decl := rf.Decls[0]
+
+	from := old.Pos().File()
+	to := token.NewFile(from.Name(), from.Base(), len(text))
+	to.SetLinesForContent(text)
+
+	decls := make([]ast.Decl, len(f.Decls))
+	copy(decls, f.Decls)
+	for i, d := range decls {
+		switch {
+		case i < index:
+			rebase(d, from, to, 0)
+		case i > index:
+			rebase(d, from, to, delta)
+		}
+	}
+	rebase(decl, decl.Pos().File(), to, start)
+	ast.SetRelPos(decl, old.Pos().RelPos())
+	decls[index] = decl
+
+	nf := &ast.File{
+		Filename: f.Filename,
+		Decls:    decls,
+		Imports:  f.Imports,
+	}
+	for _, cg := range f.Comments() {
+		rebase(cg, from, to, 0)
+		nf.AddComment(cg)
+	}
+
+	var errs errors.Error
+	astutil.Resolve(nf, func(pos token.Pos, msg string, args ...interface{}) {
+		errs = errors.Append(errs, errors.Newf(pos, msg, args...))
+	})
+	if errs != nil {
+		return false
+	}
+	*f = *nf
+	return true
+}
+
+// isReparsable reports whether a declaration can be parsed in isolation
+// without affecting the remainder of the file.
+func isReparsable(d ast.Decl) bool {
+	switch d.(type) {
+	case *ast.Package, *ast.ImportDecl, *ast.Attribute, *ast.BadDecl:
+		return false
+	}
+	return true
+}
+
+// rebase moves all positions in n that refer to the file from to the file
+// to, offset by delta bytes. It also clears the resolved references of
+// identifiers, so that they can be resolved again.
+func rebase(n ast.Node, from, to *token.File, delta int) {
+	move := func(p *token.Pos) {
+		if p.File() == from {
+			*p = to.Pos(p.Offset()+delta, p.RelPos())
+		}
+	}
+	ast.Walk(n, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.Comment:
+			move(&x.Slash)
+		case *ast.Attribute:
+			move(&x.At)
+		case *ast.Field:
+			move(&x.Optional)
+			move(&x.TokenPos)
+		case *ast.Alias:
+			move(&x.Equal)
+		case *ast.BadExpr:
+			move(&x.From)
+			move(&x.To)
+		case *ast.BottomLit:
+			move(&x.Bottom)
+		case *ast.Ident:
+			move(&x.NamePos)
+			x.Node = nil
+			x.Scope = nil
+		case *ast.BasicLit:
+			move(&x.ValuePos)
+		case *ast.StructLit:
+			move(&x.Lbrace)
+			move(&x.Rbrace)
+		case *ast.ListLit:
+			move(&x.Lbrack)
+			move(&x.Rbrack)
+		case *ast.Ellipsis:
+			move(&x.Ellipsis)
+		case *ast.ForClause:
+			move(&x.For)
+			move(&x.Colon)
+			move(&x.In)
+		case *ast.IfClause:
+			move(&x.If)
+		case *ast.LetClause:
+			move(&x.Let)
+			move(&x.Equal)
+		case *ast.ParenExpr:
+			move(&x.Lparen)
+			move(&x.Rparen)
+		case *ast.IndexExpr:
+			move(&x.Lbrack)
+			move(&x.Rbrack)
+		case *ast.SliceExpr:
+			move(&x.Lbrack)
+			move(&x.Rbrack)
+		case *ast.CallExpr:
+			move(&x.Lparen)
+			move(&x.Rparen)
+		case *ast.UnaryExpr:
+			move(&x.OpPos)
+		case *ast.BinaryExpr:
+			move(&x.OpPos)
+		case *ast.ImportSpec:
+			move(&x.EndPos)
+		case *ast.BadDecl:
+			move(&x.From)
+			move(&x.To)
+		case *ast.ImportDecl:
+			move(&x.Import)
+			move(&x.Lparen)
+			move(&x.Rparen)
+		case *ast.Package:
+			move(&x.PackagePos)
+		}
+		return true
+	}, nil)
+}
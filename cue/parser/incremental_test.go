@@ -0,0 +1,137 @@
+// Copyright 2022 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/token"
+)
+
+func TestReparseFile(t *testing.T) {
+	sources := []string{`
+package foo
+
+import "strings"
+
+a: 1
+b: {
+	c: a + 2
+	d: [1, 2, c]
+}
+e: strings.ToUpper("foo")
+#D: x: int
+f: #D & {x: b.c}
+`, `a: 1, b: 2
+c: {d: a} // comment
+
+// doc
+e: "\(b)"
+let X = 3
+g: [for x in [1, 2] {x + X}]
+`,
+	}
+	edits := []string{"", "x", "1", " ", ",", "\n", "+", "{", "}", `"`, "//"}
+
+	for _, comments := range []bool{false, true} {
+		var opts []Option
+		if comments {
+			opts = append(opts, ParseComments)
+		}
+		reused := 0
+		for _, src := range sources {
+			for start := 0; start <= len(src); start++ {
+				for end := start; end <= start+2 && end <= len(src); end++ {
+					for _, text := range edits {
+						edit := Edit{Start: start, End: end, Text: []byte(text)}
+						old, err := ParseFile("test.cue", src, opts...)
+						if err != nil {
+							t.Fatal(err)
+						}
+
+						edited := src[:start] + text + src[end:]
+						want, wantErr := ParseFile("test.cue", edited, opts...)
+						got, gotErr := ReparseFile(old, []byte(src), edit, opts...)
+						if got == old {
+							reused++
+						}
+
+						if fmt.Sprint(gotErr) != fmt.Sprint(wantErr) {
+							t.Fatalf("%q: got error %v; want %v", edited, gotErr, wantErr)
+						}
+						if g, w := dumpPositions(got), dumpPositions(want); g != w {
+							t.Fatalf("%q:\ngot:\n%s\nwant:\n%s", edited, g, w)
+						}
+					}
+				}
+			}
+		}
+		if reused == 0 {
+			t.Errorf("comments=%v: no declarations were reparsed incrementally", comments)
+		}
+	}
+}
+
+func TestReparseFileInvalidEdit(t *testing.T) {
+	src := []byte("a: 1")
+	f, err := ParseFile("test.cue", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ReparseFile(f, src, Edit{Start: 3, End: 5})
+	if err == nil {
+		t.Fatal("expected error for out-of-range edit")
+	}
+}
+
+// dumpPositions prints all nodes of f with their positions and, for
+// identifiers, the position of the node they resolve to.
+func dumpPositions(f *ast.File) string {
+	b := &strings.Builder{}
+	pos := func(p token.Pos) string {
+		return fmt.Sprintf("%v@%d(%v)", p, p.Offset(), p.RelPos())
+	}
+	ast.Walk(f, func(n ast.Node) bool {
+		fmt.Fprintf(b, "%T", n)
+		v := reflect.ValueOf(n).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Field(i).CanInterface() {
+				continue
+			}
+			if p, ok := v.Field(i).Interface().(token.Pos); ok {
+				fmt.Fprintf(b, " %s=%s", v.Type().Field(i).Name, pos(p))
+			}
+		}
+		if x, ok := n.(*ast.Ident); ok {
+			fmt.Fprintf(b, " %s", x.Name)
+			if x.Node != nil {
+				fmt.Fprintf(b, " node=%s", pos(x.Node.Pos()))
+			}
+			if x.Scope != nil {
+				fmt.Fprintf(b, " scope=%s", pos(x.Scope.Pos()))
+			}
+		}
+		b.WriteString("\n")
+		return true
+	}, nil)
+	for _, x := range f.Unresolved {
+		fmt.Fprintf(b, "unresolved %s %s\n", x.Name, pos(x.Pos()))
+	}
+	return b.String()
+}
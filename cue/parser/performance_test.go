@@ -15,7 +15,9 @@
 package parser
 
 import (
+	"fmt"
 	"io/ioutil"
+	"strings"
 	"testing"
 )
 
@@ -37,3 +39,21 @@ func BenchmarkParse(b *testing.B) {
 		}
 	}
 }
+
+// BenchmarkParseLarge parses a large configuration in which the same field
+// labels are used many times.
+func BenchmarkParseLarge(b *testing.B) {
+	var buf strings.Builder
+	for i := 0; i < 10000; i++ {
+		fmt.Fprintf(&buf, "f%d: {name: \"n\", port: 1, enabled: true, replicas: 2}\n", i)
+	}
+	src := []byte(buf.String())
+	b.SetBytes(int64(len(src)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseFile("", src); err != nil {
+			b.Fatalf("benchmark failed due to parse error: %s", err)
+		}
+	}
+}
@@ -82,3 +82,31 @@ outer:
 	}
 	return makeValue(v.idx, n, parent)
 }
+
+// LookupPathOpt is like LookupPath, but additionally reports whether the value
+// for path p exists and, if it does not, whether p refers to an optional field
+// that is not set.
+//
+// If p refers to a regular field, exists is true. If p refers to a field that
+// is not set, but that is declared optional or allowed by a pattern
+// constraint, exists is false, optional is true, and val is the constraint
+// that applies to this field. Otherwise, p does not refer to a field in the
+// schema: both exists and optional are false and val is the error value as
+// returned by LookupPath.
+func (v Value) LookupPathOpt(p Path) (val Value, exists, optional bool) {
+	val = v.LookupPath(p)
+	if val.Exists() {
+		return val, true, false
+	}
+
+	sels := append([]Selector(nil), p.Selectors()...)
+	for i, sel := range sels {
+		if sel.LabelType() == StringLabel && sel.ConstraintType() == 0 {
+			sels[i] = sel.Optional()
+		}
+	}
+	if w := v.LookupPath(MakePath(sels...)); w.Exists() {
+		return w, false, true
+	}
+	return val, false, false
+}
@@ -134,6 +134,63 @@ func TestLookupPath(t *testing.T) {
 	}
 }
 
+func TestLookupPathOpt(t *testing.T) {
+	r := &cue.Runtime{}
+	v := compileT(t, r, `
+	a: 1
+	b?: int
+	c: {
+		d?: string
+		e: {f?: bool}
+	}
+	m: [string]: {x: int}
+	#D: {g?: int}
+	`)
+
+	testCases := []struct {
+		path     string
+		out      string
+		exists   bool
+		optional bool
+	}{
+		{path: "a", out: "1", exists: true},
+		{path: "b", out: "int", optional: true},
+		{path: "c.d", out: "string", optional: true},
+		{path: "c.e.f", out: "bool", optional: true},
+		{path: "m.k", out: "{x: int}", optional: true},
+		{path: "m.k.x", out: "int", optional: true},
+		{path: "#D.g", out: "int", optional: true},
+		{path: "z"},
+		{path: "c.z"},
+		{path: "a.b"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			p := cue.ParsePath(tc.path)
+			got, exists, optional := v.LookupPathOpt(p)
+			if exists != tc.exists || optional != tc.optional {
+				t.Errorf("got exists=%v optional=%v; want exists=%v optional=%v",
+					exists, optional, tc.exists, tc.optional)
+			}
+			if p.String() != tc.path {
+				t.Errorf("path was modified: %v", p)
+			}
+			if tc.out == "" {
+				if got.Exists() {
+					t.Errorf("got %v; want non-existing value", got)
+				}
+				return
+			}
+			w := compileT(t, r, tc.out)
+			if k, d := diff.Diff(got, w); k != diff.Identity {
+				b := &bytes.Buffer{}
+				diff.Print(b, d)
+				t.Error(b)
+			}
+		})
+	}
+}
+
 func compileT(t *testing.T, r *cue.Runtime, s string) cue.Value {
 	t.Helper()
 	inst, err := r.Compile("", s)
@@ -0,0 +1,247 @@
+// Copyright 2022 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cue
+
+import (
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/ast/astutil"
+	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/token"
+	"cuelang.org/go/internal"
+)
+
+// RenameLabels returns a copy of v in which the label of each regular field
+// is replaced with the result of calling rename on it. References to renamed
+// fields, including selectors, are updated accordingly. Definitions, hidden
+// fields, pattern constraints and dynamic fields are not renamed.
+//
+// The returned map maps each new label to its original label for all labels
+// that were changed, so that the original names can be recovered, for
+// instance when exporting. As the map is global, rename must not map
+// different labels to the same label.
+//
+// An error is reported if a renamed field is referenced by an identifier and
+// its new label is not a valid identifier, or if a renamed identifier would
+// refer to a different field.
+func (v Value) RenameLabels(rename func(label string) string) (Value, map[string]string, error) {
+	if err := v.Err(); err != nil {
+		return v, nil, err
+	}
+	var f *ast.File
+	switch x := v.Syntax(Docs(true), Optional(true), Definitions(true), Hidden(true)).(type) {
+	case *ast.File:
+		f = x
+	case ast.Expr:
+		f = &ast.File{Decls: []ast.Decl{&ast.EmbedDecl{Expr: x}}}
+	}
+
+	r := &renamer{
+		rename:   rename,
+		mapping:  map[string]string{},
+		original: map[string]string{},
+		fields:   map[ast.Node]string{},
+		refs:     map[*ast.Ident]ast.Node{},
+	}
+	if err := r.file(f); err != nil {
+		return v, nil, err
+	}
+
+	w := v.Context().BuildFile(f)
+	if err := w.Err(); err != nil {
+		return v, nil, err
+	}
+	return w, r.original, nil
+}
+
+type renamer struct {
+	rename func(string) string
+
+	mapping  map[string]string // original to new label
+	original map[string]string // new to original label
+
+	// fields holds the values of the renamed fields, which are the nodes to
+	// which references to these fields resolve, with their new label.
+	fields map[ast.Node]string
+
+	// refs holds the references to renamed fields with their target.
+	refs map[*ast.Ident]ast.Node
+
+	err errors.Error
+}
+
+func (r *renamer) file(f *ast.File) errors.Error {
+	resolve(f)
+
+	// Rename field labels and references to them.
+	ast.Walk(f, func(n ast.Node) bool {
+		if x, ok := n.(*ast.Field); ok {
+			r.field(x)
+		}
+		return r.err == nil
+	}, nil)
+	ast.Walk(f, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.Ident:
+			r.ident(x)
+
+		case *ast.SelectorExpr:
+			if !isImportRef(x.X) {
+				x.Sel = r.label(x.Sel)
+			}
+
+		case *ast.IndexExpr:
+			s, ok := x.Index.(*ast.BasicLit)
+			if !ok || s.Kind != token.STRING || isImportRef(x.X) {
+				break
+			}
+			if l := r.label(s); l != ast.Label(s) {
+				name, _, _ := ast.LabelName(s)
+				index := ast.NewString(r.mapping[name])
+				astutil.CopyMeta(index, s)
+				x.Index = index
+			}
+		}
+		return r.err == nil
+	}, nil)
+	if r.err != nil {
+		return r.err
+	}
+
+	// Verify that renamed identifiers still refer to the same fields.
+	resolve(f)
+	for x, target := range r.refs {
+		if x.Node != target {
+			return errors.Newf(x.Pos(),
+				"renaming %s to %s would change the field to which it refers",
+				r.original[x.Name], x.Name)
+		}
+	}
+	return nil
+}
+
+// field renames the label of x, if applicable.
+func (r *renamer) field(x *ast.Field) {
+	label := x.Label
+	alias, _ := label.(*ast.Alias)
+	if alias != nil {
+		label, _ = alias.Expr.(ast.Label)
+	}
+	name, isIdent, err := ast.LabelName(label)
+	if err != nil || (isIdent && internal.IsDefOrHidden(name)) {
+		return // dynamic or non-regular field
+	}
+	switch label.(type) {
+	case *ast.Ident, *ast.BasicLit:
+	default:
+		return
+	}
+	newLabel := r.label(label)
+	if r.err != nil || newLabel == label {
+		return
+	}
+	if alias != nil {
+		alias.Expr = newLabel.(ast.Expr)
+	} else {
+		x.Label = newLabel
+	}
+	value := x.Value
+	if a, ok := value.(*ast.Alias); ok {
+		value = a.Expr
+	}
+	r.fields[value] = r.mapping[name]
+}
+
+// ident renames x if it refers to a renamed field.
+func (r *renamer) ident(x *ast.Ident) {
+	name, ok := r.fields[x.Node]
+	if !ok || x.Name == name {
+		return
+	}
+	if !ast.IsValidIdent(name) || internal.IsDefOrHidden(name) {
+		r.err = errors.Newf(x.Pos(),
+			"cannot rename %s to %q: field is referenced and %q is not a valid identifier",
+			x.Name, name, name)
+		return
+	}
+	r.refs[x] = x.Node
+	x.Name = name
+}
+
+// label returns the renamed version of the regular label l, or l itself if it
+// is unchanged or not a regular label.
+func (r *renamer) label(l ast.Label) ast.Label {
+	name, isIdent, err := ast.LabelName(l)
+	if err != nil || (isIdent && internal.IsDefOrHidden(name)) {
+		return l
+	}
+	newName, ok := r.mapping[name]
+	if !ok {
+		newName = r.rename(name)
+		if newName == "" {
+			r.err = errors.Newf(l.Pos(), "cannot rename %s to empty label", name)
+			return l
+		}
+		if orig, ok := r.original[newName]; ok && orig != name {
+			r.err = errors.Newf(l.Pos(),
+				"labels %q and %q both renamed to %q", orig, name, newName)
+			return l
+		}
+		r.mapping[name] = newName
+		if newName != name {
+			r.original[newName] = name
+		}
+	}
+	if newName == name {
+		return l
+	}
+
+	var x ast.Label = ast.NewString(newName)
+	if ast.IsValidIdent(newName) && !internal.IsDefOrHidden(newName) {
+		x = ast.NewIdent(newName)
+	}
+	astutil.CopyMeta(x, l)
+	return x
+}
+
+// resolve (re)resolves all identifiers in f.
+func resolve(f *ast.File) {
+	ast.Walk(f, func(n ast.Node) bool {
+		if x, ok := n.(*ast.Ident); ok {
+			x.Node = nil
+			x.Scope = nil
+		}
+		return true
+	}, nil)
+	f.Unresolved = nil
+	astutil.Resolve(f, func(token.Pos, string, ...interface{}) {})
+}
+
+// isImportRef reports whether x is a reference to an imported package, or a
+// selection thereof.
+func isImportRef(x ast.Expr) bool {
+	for {
+		switch y := x.(type) {
+		case *ast.SelectorExpr:
+			x = y.X
+		case *ast.IndexExpr:
+			x = y.X
+		case *ast.Ident:
+			_, ok := y.Node.(*ast.ImportSpec)
+			return ok
+		default:
+			return false
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2022 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cue_test
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/cue/format"
+)
+
+func TestRenameLabels(t *testing.T) {
+	// goName converts labels to exported Go identifiers.
+	goName := func(s string) string {
+		var b strings.Builder
+		for _, part := range strings.FieldsFunc(s, func(r rune) bool {
+			return r == '-' || r == '_' || r == '.' || r == ' '
+		}) {
+			b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+		return b.String()
+	}
+	testCases := []struct {
+		name   string
+		in     string
+		rename func(string) string
+		out    string
+		labels string
+		err    string
+	}{{
+		name: "references",
+		in: `
+		import "strings"
+
+		"max-replicas": 3
+		min_replicas:   1
+		limits: {
+			"cpu.max": 2
+			total:     min_replicas + limits["cpu.max"]
+		}
+		copy:  limits.total
+		upper: strings.ToUpper("x")
+		#Def: {"port-number": int}
+		d: #Def & {"port-number": 80}
+		_hidden: min_replicas
+		`,
+		rename: goName,
+		out: `import "strings"
+
+MaxReplicas: 3
+MinReplicas: 1
+Limits: {
+	CpuMax: 2
+	Total:  MinReplicas + Limits["CpuMax"]
+}
+Copy:  Limits.Total
+Upper: strings.ToUpper("x")
+#Def: {
+	PortNumber: int
+}
+D: #Def & {
+	PortNumber: 80
+}
+_hidden: MinReplicas
+`,
+		labels: "Copy:copy CpuMax:cpu.max D:d Limits:limits MaxReplicas:max-replicas MinReplicas:min_replicas PortNumber:port-number Total:total Upper:upper",
+	}, {
+		name:   "unchanged",
+		in:     `a: 1, b: a`,
+		rename: func(s string) string { return s },
+		out:    "{\n\ta: 1\n\tb: a\n}",
+		labels: "",
+	}, {
+		name:   "invalid identifier",
+		in:     `a: 1, b: a`,
+		rename: func(s string) string { return s + "-x" },
+		err:    `cannot rename a to "a-x": field is referenced and "a-x" is not a valid identifier`,
+	}, {
+		name:   "invalid identifier unreferenced",
+		in:     `a: 1, b: 2`,
+		rename: func(s string) string { return s + "-x" },
+		out:    "{\n\t\"a-x\": 1\n\t\"b-x\": 2\n}",
+		labels: "a-x:a b-x:b",
+	}, {
+		name:   "conflict",
+		in:     `a_b: 1, "a-b": 2`,
+		rename: goName,
+		err:    `labels "a_b" and "a-b" both renamed to "AB"`,
+	}, {
+		name: "shadowing",
+		in: `
+		a: 1
+		b: {
+			let x = 2
+			c: a + x
+		}
+		`,
+		rename: func(s string) string {
+			if s == "a" {
+				return "x"
+			}
+			return s
+		},
+		err: "renaming a to x would change the field to which it refers",
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := cuecontext.New()
+			v := ctx.CompileString(tc.in)
+			w, labels, err := v.RenameLabels(tc.rename)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("got error %v; want %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := format.Node(w.Syntax(cue.Definitions(true), cue.Hidden(true)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(b); got != tc.out {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tc.out)
+			}
+			if err := w.Validate(); err != nil {
+				t.Error(err)
+			}
+			var a []string
+			for k, v := range labels {
+				a = append(a, fmt.Sprintf("%s:%s", k, v))
+			}
+			sort.Strings(a)
+			if got := strings.Join(a, " "); got != tc.labels {
+				t.Errorf("got labels %s; want %s", got, tc.labels)
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright 2022 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cue
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/literal"
+	"cuelang.org/go/cue/token"
+	"cuelang.org/go/internal/astinternal"
+)
+
+// jsonSyntax generates the syntax for v using only constructs that can be
+// represented in JSON. If this is not possible, it returns a bottom literal
+// with the error as a comment.
+func (v Value) jsonSyntax(opts []Option) ast.Node {
+	if err := v.Validate(Concrete(true)); err != nil {
+		return errorSyntax(err)
+	}
+	n := v.Syntax(append(opts, JSON(false))...)
+	if err := toJSONSyntax(n); err != nil {
+		return errorSyntax(err)
+	}
+	return n
+}
+
+func errorSyntax(err error) *ast.BottomLit {
+	x := &ast.BottomLit{}
+	x.AddComment(&ast.CommentGroup{
+		Line:     true,
+		Position: 2,
+		List:     []*ast.Comment{{Text: "// " + err.Error()}},
+	})
+	return x
+}
+
+// toJSONSyntax rewrites n in place to use only constructs that can be
+// represented in JSON. It reports an error for any other construct.
+func toJSONSyntax(n ast.Node) (err error) {
+	ast.Walk(n, func(n ast.Node) bool {
+		if err != nil {
+			return false
+		}
+		switch x := n.(type) {
+		case *ast.File:
+			for _, d := range x.Decls {
+				if _, ok := d.(*ast.EmbedDecl); !ok {
+					err = notJSON(d)
+					return false
+				}
+			}
+
+		case *ast.EmbedDecl, *ast.StructLit, *ast.ListLit, *ast.CommentGroup,
+			*ast.Comment:
+
+		case *ast.Field:
+			if x.Optional != token.NoPos || len(x.Attrs) > 0 {
+				err = notJSON(x)
+				return false
+			}
+			name, _, lerr := ast.LabelName(x.Label)
+			if lerr != nil {
+				err = notJSON(x.Label)
+				return false
+			}
+			label := ast.NewString(name)
+			ast.SetPos(label, x.Label.Pos())
+			ast.SetComments(label, ast.Comments(x.Label))
+			x.Label = label
+
+		case *ast.BasicLit:
+			err = toJSONLit(x)
+
+		case *ast.UnaryExpr:
+			// Negative numbers.
+			lit, ok := x.X.(*ast.BasicLit)
+			if x.Op != token.SUB || !ok ||
+				(lit.Kind != token.INT && lit.Kind != token.FLOAT) {
+				err = notJSON(x)
+			}
+
+		default:
+			err = notJSON(n)
+		}
+		return err == nil
+	}, nil)
+	return err
+}
+
+func toJSONLit(x *ast.BasicLit) error {
+	switch x.Kind {
+	case token.NULL, token.TRUE, token.FALSE:
+		return nil
+
+	case token.INT, token.FLOAT:
+		if !json.Valid([]byte(x.Value)) {
+			return notJSON(x)
+		}
+		return nil
+
+	case token.STRING:
+		info, _, _, err := literal.ParseQuotes(x.Value, x.Value)
+		if err != nil {
+			return err
+		}
+		s, err := literal.Unquote(x.Value)
+		if err != nil {
+			return err
+		}
+		if !info.IsDouble() {
+			// Bytes are represented as base64 in JSON.
+			s = base64.StdEncoding.EncodeToString([]byte(s))
+		}
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(s); err != nil {
+			return err
+		}
+		x.Value = string(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+		return nil
+	}
+	return notJSON(x)
+}
+
+func notJSON(n ast.Node) error {
+	return errors.Newf(n.Pos(), "cannot represent %s in JSON", astinternal.DebugStr(n))
+}
+
+type typeAnnotation int8
+
+const (
+	noTypes typeAnnotation = iota
+	typeComments
+	typeConstraints
+)
+
+// typedSyntax generates the syntax for v, annotating each concrete scalar
+// leaf with its type as specified by mode.
+func (v Value) typedSyntax(mode typeAnnotation, opts []Option) ast.Node {
+	n := v.Syntax(append(opts, func(p *options) { p.types = noTypes })...)
+	if x, ok := n.(ast.Expr); ok {
+		// The value itself may be a scalar.
+		n = annotateType(x, mode)
+	}
+	ast.Walk(n, nil, func(n ast.Node) {
+		switch x := n.(type) {
+		case *ast.Field:
+			x.Value = annotateType(x.Value, mode)
+
+		case *ast.EmbedDecl:
+			x.Expr = annotateType(x.Expr, mode)
+
+		case *ast.ListLit:
+			for i, e := range x.Elts {
+				if mode == typeComments && litType(e) != "" {
+					// Place each element on its own line so that the
+					// comments do not end up between elements.
+					ast.SetRelPos(e, token.Newline)
+				}
+				x.Elts[i] = annotateType(e, mode)
+			}
+		}
+	})
+	return n
+}
+
+// annotateType annotates x with its type if it is a scalar literal. In
+// comment mode, x is annotated in place and returned.
+func annotateType(x ast.Expr, mode typeAnnotation) ast.Expr {
+	typ := litType(x)
+	if typ == "" {
+		return x
+	}
+	switch mode {
+	case typeComments:
+		ast.AddComment(x, &ast.CommentGroup{
+			Line:     true,
+			Position: 1,
+			List:     []*ast.Comment{{Text: "// " + typ}},
+		})
+		return x
+
+	case typeConstraints:
+		return ast.NewBinExpr(token.AND, x, ast.NewIdent(typ))
+	}
+	return x
+}
+
+// litType returns the type of x if it is a scalar literal, or "" otherwise.
+func litType(x ast.Expr) string {
+	if u, ok := x.(*ast.UnaryExpr); ok && (u.Op == token.SUB || u.Op == token.ADD) {
+		// Signed numbers.
+		if lit, ok := u.X.(*ast.BasicLit); ok &&
+			(lit.Kind == token.INT || lit.Kind == token.FLOAT) {
+			x = lit
+		}
+	}
+	lit, ok := x.(*ast.BasicLit)
+	if !ok {
+		return ""
+	}
+	switch lit.Kind {
+	case token.NULL:
+		return "null"
+	case token.TRUE, token.FALSE:
+		return "bool"
+	case token.INT:
+		return "int"
+	case token.FLOAT:
+		return "float"
+	case token.STRING:
+		info, _, _, err := literal.ParseQuotes(lit.Value, lit.Value)
+		if err != nil {
+			return ""
+		}
+		if !info.IsDouble() {
+			return "bytes"
+		}
+		return "string"
+	}
+	return ""
+}
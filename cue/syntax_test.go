@@ -61,6 +61,28 @@ func TestSyntax(t *testing.T) {
 			hello2: "world"
 		}
 	}
+}`,
+	}, {
+		name: "omit docs",
+		in: `
+		// Aloha
+		hello: "world" // inline
+		#Def: {
+			// Aloha2
+			a: int
+		}
+		b: #Def & {a: 1}
+		`,
+		options: o(cue.Docs(true), cue.Docs(false), cue.ResolveReferences(true)),
+		out: `
+{
+	hello: "world"
+	#Def: {
+		a: int
+	}
+	b: {
+		a: 1
+	}
 }`,
 	}, {
 		name: "partially resolvable",
@@ -198,6 +220,110 @@ func TestSyntax(t *testing.T) {
 	}
 }
 		`,
+	}, {
+		name: "json",
+		in: `
+		#D: {a: int}
+		d: #D & {a: 1}
+		_h: 2
+		opt?: 3
+		ident: "foo" @attr()
+		"quoted-label": true
+		ref: ident
+		default: *1 | 2
+		neg: -1.5
+		n: null
+		multi: """
+			a
+			b
+			"""
+		raw: #"a\b"#
+		bytes: 'abc'
+		list: [1, {x: "<>"}]
+		`,
+		options: o(cue.JSON(true)),
+		out: `
+{
+	"d": {
+		"a": 1
+	}
+	"ident":        "foo"
+	"quoted-label": true
+	"ref":          "foo"
+	"default":      1
+	"neg":          -1.5
+	"n":            null
+	"multi":        "a\nb"
+	"raw":          "a\\b"
+	"bytes":        "YWJj"
+	"list": [1, {
+		"x": "<>"
+	}]
+}`,
+	}, {
+		name:    "json incomplete",
+		in:      `a: int`,
+		options: o(cue.JSON(true)),
+		out:     `_|_ // a: incomplete value int`,
+	}, {
+		name:    "json scalar",
+		in:      `a: "foo\tbar"`,
+		path:    "a",
+		options: o(cue.JSON(true)),
+		out:     `"foo\tbar"`,
+	}, {
+		name: "type comments",
+		in: `
+		port: 8080
+		ratio: 1.5
+		exp: 1e3
+		neg: -2
+		name: "web"
+		raw: 'abc'
+		ok: true
+		n: null
+		list: [1, "x", {a: 2.0}]
+		t: int
+		`,
+		options: o(cue.Final(), cue.TypeAnnotations(false)),
+		out: `
+{
+	port:  8080  // int
+	ratio: 1.5   // float
+	exp:   1e+3  // float
+	neg:   -2    // int
+	name:  "web" // string
+	raw:   'abc' // bytes
+	ok:    true  // bool
+	n:     null  // null
+	list: [
+		1,   // int
+		"x", // string
+		{
+			a: 2.0 // float
+		}]
+	t: int
+}`,
+	}, {
+		name: "type constraints",
+		in: `
+		port: 8080
+		list: [1, "x"]
+		d: *1 | 2
+		`,
+		options: o(cue.TypeAnnotations(true)),
+		out: `
+{
+	port: 8080 & int
+	list: [1 & int, "x" & string]
+	d: *1 | 2
+}`,
+	}, {
+		name:    "type scalar",
+		in:      `a: 1.0`,
+		path:    "a",
+		options: o(cue.TypeAnnotations(true)),
+		out:     `1.0 & float`,
 	}}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -219,3 +345,95 @@ func TestSyntax(t *testing.T) {
 		})
 	}
 }
+
+// TestSyntaxTypeComments verifies that type comments do not change the
+// meaning of the generated syntax.
+func TestSyntaxTypeComments(t *testing.T) {
+	const in = `
+	a: 1
+	b: [1, -2.5, "x", 'y', [true, null], {c: 3}]
+	d: {e: "f", g: [4]}
+	`
+	ctx := cuecontext.New()
+	v := ctx.CompileString(in)
+	b, err := format.Node(v.Syntax(cue.Final(), cue.TypeAnnotations(false)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := ctx.CompileBytes(b)
+	if err := w.Err(); err != nil {
+		t.Fatalf("reparse failed: %v\n%s", err, b)
+	}
+	if !w.Equals(v) {
+		t.Errorf("got %v; want %v", w, v)
+	}
+}
+
+// TestSyntaxSelfContained verifies that the syntax of a sub-value does not
+// refer to fields outside this sub-value and evaluates to the same value when
+// compiled on its own.
+func TestSyntaxSelfContained(t *testing.T) {
+	testCases := []struct {
+		name string
+		in   string
+		path string
+	}{{
+		name: "reference to sibling of ancestor",
+		in: `
+		x: {
+			a: {b: c.d, e: b}
+			c: d: int
+		}
+		`,
+		path: "x.a",
+	}, {
+		name: "reference to definition",
+		in: `
+		#D: {h: int, i?: string}
+		x: a: {d: #D, e: #D & {h: 1}}
+		`,
+		path: "x.a",
+	}, {
+		name: "reference to disjunction",
+		in: `
+		#Kind: *"foo" | "bar"
+		z: w: 1 | 2
+		x: a: {k: #Kind, w: z.w}
+		`,
+		path: "x.a",
+	}, {
+		name: "comprehension over ancestor field",
+		in: `
+		names: ["a", "b"]
+		x: a: {for n in names {(n): n}}
+		`,
+		path: "x.a",
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := cuecontext.New()
+
+			v := ctx.CompileString(tc.in).LookupPath(cue.ParsePath(tc.path))
+			if err := v.Err(); err != nil {
+				t.Fatal(err)
+			}
+			b, err := format.Node(v.Syntax())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			w := ctx.CompileBytes(b)
+			if err := w.Err(); err != nil {
+				t.Fatalf("syntax does not compile: %v\n%s", err, b)
+			}
+			// Let clauses introduced for references to values outside v are
+			// represented as fields, so compare final values only.
+			if err := v.Subsume(w, cue.Final()); err != nil {
+				t.Errorf("original does not subsume result: %v\n%s", err, b)
+			}
+			if err := w.Subsume(v, cue.Final()); err != nil {
+				t.Errorf("result does not subsume original: %v\n%s", err, b)
+			}
+		})
+	}
+}
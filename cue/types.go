@@ -21,6 +21,7 @@ import (
 	"io"
 	"math"
 	"math/big"
+	"sort"
 	"strings"
 
 	"github.com/cockroachdb/apd/v2"
@@ -229,6 +230,10 @@ type Iterator struct {
 	cur   Value
 	f     adt.Feature
 	isOpt bool
+
+	// indexed reports whether the index of each field in arcs is set.
+	// For structs, indices are computed on the first call to Index.
+	indexed bool
 }
 
 type hiddenIterator = Iterator
@@ -236,6 +241,7 @@ type hiddenIterator = Iterator
 type field struct {
 	arc        *adt.Vertex
 	isOptional bool
+	index      int
 }
 
 // Next advances the iterator to the next value and reports whether there was
@@ -285,6 +291,23 @@ func (i *hiddenIterator) IsHidden() bool {
 	return i.f.IsHidden()
 }
 
+// Index reports the position of the current value. For lists this is the index
+// of the element. For structs this is the position of the field when ordering
+// all iterated fields by the earliest source position at which they are
+// defined. Fields without a source position are ordered last. Unlike the
+// iteration order, this position does not depend on the order in which fields
+// are evaluated, allowing fields to be presented in a stable order.
+func (i *Iterator) Index() int {
+	if i.p == 0 {
+		return 0
+	}
+	if !i.indexed {
+		setSourceIndices(i.arcs)
+		i.indexed = true
+	}
+	return i.arcs[i.p-1].index
+}
+
 // IsOptional reports if a field is optional.
 func (i *Iterator) IsOptional() bool {
 	return i.isOpt
@@ -901,6 +924,11 @@ func (v Value) Kind() Kind {
 }
 
 // IncompleteKind returns a mask of all kinds that this value may be.
+//
+// Unlike Kind, which only reports the kind of concrete values, IncompleteKind
+// reports the kinds that a value may still take given its constraints. For
+// instance, it returns TopKind for _, StringKind for string, and
+// StructKind|ListKind for [...int] | {a?: int}.
 func (v Value) IncompleteKind() Kind {
 	if v.v == nil {
 		return BottomKind
@@ -964,6 +992,14 @@ func (v Value) marshalJSON() (b []byte, err error) {
 
 // Syntax converts the possibly partially evaluated value into syntax. This
 // can use used to print the value with package format.
+//
+// The result is self-contained: references to values outside of v, such as
+// fields of ancestors of v, are resolved or, if they need to be retained,
+// rewritten to refer to let clauses that are added to the result. As a
+// consequence, the syntax of a sub-value can be compiled on its own.
+// References that cannot be resolved, such as selections of fields that do
+// not exist, are retained in this form as well, resulting in the same error
+// when the result is evaluated, rather than being reported by Syntax.
 func (v Value) Syntax(opts ...Option) ast.Node {
 	// TODO: the default should ideally be simplified representation that
 	// exactly represents the value. The latter can currently only be
@@ -972,6 +1008,12 @@ func (v Value) Syntax(opts ...Option) ast.Node {
 		return nil
 	}
 	var o options = getOptions(opts)
+	if o.json {
+		return v.jsonSyntax(opts)
+	}
+	if o.types != noTypes {
+		return v.typedSyntax(o.types, opts)
+	}
 	// var inst *Instance
 
 	p := export.Profile{
@@ -1207,6 +1249,25 @@ func (v Value) Exists() bool {
 	return true
 }
 
+// FromComprehension reports whether any of the conjuncts of v originated from
+// a comprehension, such as a for or if clause, rather than being written out
+// statically. This also holds for values nested within a field that was
+// generated by a comprehension.
+//
+// A field may be defined both statically and by a comprehension. In that case
+// FromComprehension reports true.
+func (v Value) FromComprehension() bool {
+	if v.v == nil {
+		return false
+	}
+	for _, c := range v.v.Conjuncts {
+		if c.CloseInfo.IsInOneOf(adt.ComprehensionSpan) {
+			return true
+		}
+	}
+	return false
+}
+
 func (v Value) checkKind(ctx *adt.OpContext, want adt.Kind) *adt.Bottom {
 	if v.v == nil {
 		return errNotExists
@@ -1293,10 +1354,10 @@ func (v Value) List() (Iterator, error) {
 	arcs := []field{}
 	for _, a := range v.v.Elems() {
 		if a.Label.IsInt() {
-			arcs = append(arcs, field{arc: a})
+			arcs = append(arcs, field{arc: a, index: len(arcs)})
 		}
 	}
-	return Iterator{idx: v.idx, ctx: ctx, val: v, arcs: arcs}, nil
+	return Iterator{idx: v.idx, ctx: ctx, val: v, arcs: arcs, indexed: true}, nil
 }
 
 // Null reports an error if v is not null.
@@ -1527,6 +1588,42 @@ func (v Value) Fields(opts ...Option) (*Iterator, error) {
 	return &Iterator{idx: v.idx, ctx: ctx, val: v, arcs: arcs}, nil
 }
 
+// setSourceIndices sets the index of each field to its position when ordered
+// by the earliest source position of its conjuncts.
+func setSourceIndices(arcs []field) {
+	pos := make([]token.Pos, len(arcs))
+	order := make([]int, len(arcs))
+	for i, f := range arcs {
+		order[i] = i
+		for _, c := range f.arc.Conjuncts {
+			src := c.Source()
+			if src == nil {
+				continue
+			}
+			if p := src.Pos(); p.IsValid() && (!pos[i].IsValid() || posLess(p, pos[i])) {
+				pos[i] = p
+			}
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := pos[order[i]], pos[order[j]]
+		if !b.IsValid() {
+			return a.IsValid()
+		}
+		return a.IsValid() && posLess(a, b)
+	})
+	for i, k := range order {
+		arcs[k].index = i
+	}
+}
+
+func posLess(a, b token.Pos) bool {
+	if fa, fb := a.Filename(), b.Filename(); fa != fb {
+		return fa < fb
+	}
+	return a.Offset() < b.Offset()
+}
+
 // Lookup reports the value at a path starting from v. The empty path returns v
 // itself.
 //
@@ -1859,16 +1956,56 @@ func addConjuncts(dst, src *adt.Vertex) {
 
 // Unify reports the greatest lower bound of v and w.
 //
+// The ListMerge option may be used to change how lists of different lengths
+// are combined.
+//
 // Value v and w must be obtained from the same build.
 // TODO: remove this requirement.
-func (v Value) Unify(w Value) Value {
+func (v Value) Unify(w Value, opts ...Option) Value {
 	if v.v == nil {
 		return w
 	}
 	if w.v == nil || w.v == v.v {
 		return v
 	}
+	if len(opts) > 0 {
+		if o := getOptions(opts); o.hasListMerge {
+			r, _ := unifyLists(v, w, o.listMerge)
+			return r
+		}
+	}
+	if sameVertex(v.v, w.v) {
+		// Unification is idempotent.
+		return v
+	}
+	return v.unify(w)
+}
+
+// sameVertex reports whether v and w are finalized vertices that were
+// computed from the exact same set of conjuncts and thus represent the same
+// value. It is a cheap check that never recurses.
+func sameVertex(v, w *adt.Vertex) bool {
+	if v.Status() != adt.Finalized || w.Status() != adt.Finalized {
+		return false
+	}
+	if v.Closed != w.Closed || len(v.Conjuncts) == 0 ||
+		len(v.Conjuncts) != len(w.Conjuncts) {
+		return false
+	}
+	// Errors are accumulated by unification, so an error unified with itself
+	// is not identical to the original.
+	if _, ok := v.BaseValue.(*adt.Bottom); ok {
+		return false
+	}
+	for i, c := range v.Conjuncts {
+		if c != w.Conjuncts[i] {
+			return false
+		}
+	}
+	return true
+}
 
+func (v Value) unify(w Value) Value {
 	n := &adt.Vertex{}
 	addConjuncts(n, v.v)
 	addConjuncts(n, w.v)
@@ -1893,6 +2030,48 @@ func (v Value) Unify(w Value) Value {
 	return makeValue(v.idx, n, v.parent_)
 }
 
+// UnifyChanged is as v.Unify(w), but also reports whether the result differs
+// from v. The result is considered unchanged if it and v subsume each other
+// and the structs and lists they contain are closed in the same way.
+// This is useful for unifying values repeatedly until a fixpoint is reached.
+// An error is returned if the result of the unification is an error.
+//
+// Value v and w must be obtained from the same build.
+func (v Value) UnifyChanged(w Value) (u Value, changed bool, err error) {
+	switch {
+	case v.v == nil:
+		return w, w.v != nil, w.Err()
+	case w.v == nil || w.v == v.v || sameVertex(v.v, w.v):
+		return v, false, v.Err()
+	}
+	u = v.unify(w)
+	if err := u.Err(); err != nil {
+		return u, true, err
+	}
+	// The result of unification is always subsumed by v, so the result is
+	// unchanged if it also subsumes v. Subsumption does not consider
+	// closedness, though, so this is compared separately.
+	v.v.Finalize(v.ctx())
+	return u, u.Subsume(v) != nil || closednessChanged(v.v, u.v), nil
+}
+
+// closednessChanged reports whether u, or any of its fields that also exist in
+// v, is closed differently from the corresponding value in v.
+func closednessChanged(v, u *adt.Vertex) bool {
+	isClosed := func(x *adt.Vertex) bool {
+		return x.Closed || x.IsClosedStruct() || x.IsClosedList()
+	}
+	if isClosed(v) != isClosed(u) {
+		return true
+	}
+	for _, a := range u.Arcs {
+		if b := v.Lookup(a.Label); b != nil && closednessChanged(b, a) {
+			return true
+		}
+	}
+	return false
+}
+
 // UnifyAccept is as v.Unify(w), but will disregard any field that is allowed
 // in the Value accept.
 func (v Value) UnifyAccept(w Value, accept Value) Value {
@@ -1926,6 +2105,40 @@ func (v Value) UnifyAccept(w Value, accept Value) Value {
 	return makeValue(v.idx, n, v.parent_)
 }
 
+// Apply unifies the schema v with data, selects default values, and checks
+// that the result is concrete. It returns the finalized value, which only
+// contains regular fields, or an error listing all fields that could not be
+// resolved to a concrete value.
+//
+// Value v and data must be obtained from the same build.
+func (v Value) Apply(data Value) (Value, error) {
+	u := v.Unify(data)
+	if err := u.Validate(Concrete(true)); err != nil {
+		return u, err
+	}
+	if u.v == nil {
+		return u, nil
+	}
+	return makeValue(u.idx, finalizeDefaults(u.ctx(), u.v), u.parent_), nil
+}
+
+// finalizeDefaults returns a data-only copy of v in which all disjunctions
+// with defaults are resolved recursively.
+func finalizeDefaults(ctx *adt.OpContext, v *adt.Vertex) *adt.Vertex {
+	v = v.Default()
+	w := v.ToDataSingle()
+	w.Arcs = make([]*adt.Vertex, 0, len(v.Arcs))
+	for _, a := range v.Arcs {
+		if !a.Label.IsRegular() || !a.IsDefined(ctx) {
+			continue
+		}
+		a = finalizeDefaults(ctx, a)
+		a.Parent = w
+		w.Arcs = append(w.Arcs, a)
+	}
+	return w
+}
+
 // Equals reports whether two values are equal, ignoring optional fields.
 // The result is undefined for incomplete values.
 func (v Value) Equals(other Value) bool {
@@ -2058,8 +2271,16 @@ type options struct {
 	final             bool
 	ignoreClosedness  bool // used for comparing APIs
 	docs              bool
+	json              bool // only generate JSON-compatible syntax
+	types             typeAnnotation
 	disallowCycles    bool // implied by concrete
+	maxDepth          int  // maximum validation depth; 0 means unbounded
+	requireDocs       bool // require documentation for definitions
+	requireFieldDocs  bool // require documentation for regular fields
 	allowScalar       bool
+	hasListMerge      bool
+	listMerge         ListMergeStrategy
+	bytesAsBase64     bool // used by Decode
 }
 
 // An Option defines modes of evaluation.
@@ -2102,6 +2323,39 @@ func Concrete(concrete bool) Option {
 	}
 }
 
+// JSON tells Syntax to only generate constructs that can be represented in
+// JSON: all labels are quoted, strings are double-quoted, bytes are encoded
+// as base64, and references, definitions, hidden and optional fields, and
+// attributes are omitted. It implies Concrete(true).
+//
+// If the value cannot be represented as JSON, for instance because it is not
+// concrete, Syntax returns an *ast.BottomLit with a comment describing the
+// error.
+func JSON(json bool) Option {
+	return func(p *options) {
+		p.json = json
+		if json {
+			Concrete(true)(p)
+			p.omitAttrs = true
+		}
+	}
+}
+
+// TypeAnnotations tells Syntax to annotate each concrete scalar leaf, such as
+// a field value or list element, with its type: one of null, bool, int,
+// float, string, or bytes. By default the type is added as a line comment,
+// as in `port: 8080 // int`, so that the output parses as before. If
+// constraints is true, the type is instead added as a constraint, as in
+// `port: 8080 & int`.
+func TypeAnnotations(constraints bool) Option {
+	return func(p *options) {
+		p.types = typeComments
+		if constraints {
+			p.types = typeConstraints
+		}
+	}
+}
+
 // InlineImports causes references to values within imported packages to be
 // inlined. References to builtin packages are not inlined.
 func InlineImports(expand bool) Option {
@@ -2114,6 +2368,29 @@ func DisallowCycles(disallow bool) Option {
 	return func(p *options) { p.disallowCycles = disallow }
 }
 
+// MaxDepth limits the number of levels Validate descends into a value.
+// Validation fails with an error citing the deepest path reached if a value
+// is nested more than n levels deep. A value of 0 or less means there is no
+// limit, which is the default.
+//
+// MaxDepth only bounds validation, which happens after the value has been
+// evaluated. It does not bound the evaluation itself and thus cannot stop
+// recursion during evaluation. Use EvalLimits with Limits.MaxDepth for that.
+func MaxDepth(n int) Option {
+	return func(p *options) { p.maxDepth = n }
+}
+
+// RequireDocs causes Validate to report an error for each definition that
+// has neither a doc comment nor a @doc attribute. If fields is true, this
+// applies to regular fields as well. Documentation may come from any of the
+// declarations of a field.
+func RequireDocs(fields bool) Option {
+	return func(p *options) {
+		p.requireDocs = true
+		p.requireFieldDocs = fields
+	}
+}
+
 // ResolveReferences forces the evaluation of references when outputting.
 //
 // Deprecated: Syntax will now always attempt to resolve dangling references and
@@ -2161,9 +2438,10 @@ func All() Option {
 	}
 }
 
-// Docs indicates whether docs should be included.
+// Docs indicates whether doc comments should be included. Doc comments are
+// excluded by default. Comments other than doc comments are never included.
 func Docs(include bool) Option {
-	return func(p *options) { p.docs = true }
+	return func(p *options) { p.docs = include }
 }
 
 // Definitions indicates whether definitions should be included.
@@ -2196,6 +2474,28 @@ func Attributes(include bool) Option {
 	return func(p *options) { p.omitAttrs = !include }
 }
 
+// ListMerge defines how Unify combines two lists. See ListMergeStrategy for
+// the available strategies.
+//
+// Setting this option also enables the @merge(list=strategy) field attribute,
+// which overrides the strategy for a field and all values nested within it.
+//
+// The strategy only applies to lists that are combined directly. References
+// within v or w are resolved as with regular unification.
+func ListMerge(s ListMergeStrategy) Option {
+	return func(p *options) {
+		p.hasListMerge = true
+		p.listMerge = s
+	}
+}
+
+// BytesAsBase64 indicates whether Decode converts bytes values to Go strings by
+// encoding them using standard base64 encoding. By default, decoding a bytes
+// value into a string is an error.
+func BytesAsBase64(encode bool) Option {
+	return func(p *options) { p.bytesAsBase64 = encode }
+}
+
 func getOptions(opts []Option) (o options) {
 	o.updateOptions(opts)
 	return
@@ -2221,15 +2521,48 @@ func (v Value) Validate(opts ...Option) error {
 		Concrete:       o.concrete,
 		DisallowCycles: o.disallowCycles,
 		AllErrors:      true,
+		MaxDepth:       o.maxDepth,
 	}
 
-	b := validate.Validate(v.ctx(), v.v, cfg)
-	if b != nil {
-		return v.toErr(b)
+	var err errors.Error
+	if b := validate.Validate(v.ctx(), v.v, cfg); b != nil {
+		err = v.toErr(b)
+	}
+	if o.requireDocs {
+		err = errors.Append(err, v.checkDocs(o.requireFieldDocs))
+	}
+	if err != nil {
+		return err
 	}
 	return nil
 }
 
+// checkDocs reports an error for each definition, and regular field if fields
+// is true, within v that is not documented.
+func (v Value) checkDocs(fields bool) (errs errors.Error) {
+	if v.IncompleteKind() != StructKind {
+		return nil
+	}
+	iter, err := v.Fields(Definitions(true), Optional(true))
+	if err != nil {
+		return nil
+	}
+	for iter.Next() {
+		w := iter.Value()
+		sel := iter.Selector()
+		if sel.IsDefinition() || (fields && sel.IsString()) {
+			if a := w.Attribute("doc"); len(w.Doc()) == 0 && a.Err() != nil {
+				errs = errors.Append(errs, w.toErr(&adt.Bottom{
+					Code: adt.EvalError,
+					Err:  errors.Newf(w.Pos(), "missing documentation"),
+				}))
+			}
+		}
+		errs = errors.Append(errs, w.checkDocs(fields))
+	}
+	return errs
+}
+
 // Walk descends into all values of v, calling f. If f returns false, Walk
 // will not descent further. It only visits values that are part of the data
 // model, so this excludes optional fields, hidden fields, and definitions.
@@ -28,6 +28,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 
 	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/errors"
 	"cuelang.org/go/internal/astinternal"
 	"cuelang.org/go/internal/core/adt"
 	"cuelang.org/go/internal/core/debug"
@@ -334,6 +335,18 @@ func TestValueType(t *testing.T) {
 		// Hard to tell what is correct here, but For backwards compatibility,
 		// this is false.
 		closed: false,
+	}, {
+		value:          `v: _`,
+		kind:           BottomKind,
+		incompleteKind: TopKind,
+	}, {
+		value:          `v: [...int] | {a?: int}`,
+		kind:           BottomKind,
+		incompleteKind: ListKind | StructKind,
+	}, {
+		value:          `v: null | string | *5`,
+		kind:           BottomKind,
+		incompleteKind: NullKind | StringKind | IntKind,
 	}}
 	for _, tc := range testCases {
 		t.Run(tc.value, func(t *testing.T) {
@@ -830,6 +843,63 @@ func TestFields(t *testing.T) {
 	}
 }
 
+func TestFieldsIndex(t *testing.T) {
+	testCases := []struct {
+		value string
+		path  string
+		res   string
+	}{{
+		value: `x: {a: 1, b: 2, c: 3}`,
+		path:  "x",
+		res:   "a:0 b:1 c:2",
+	}, {
+		// Fields of y are inserted first, but appear later in the source.
+		value: `
+		x: y & {c: 1}
+		y: {a: 1, b: 2}`,
+		path: "x",
+		res:  "a:1 c:0 b:2",
+	}, {
+		value: `
+		x: {b: 1}
+		x: {a: 1, b: 1}`,
+		path: "x",
+		res:  "a:1 b:0",
+	}, {
+		value: `x: [3, 2, 1]`,
+		path:  "x",
+		res:   "0:0 1:1 2:2",
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			v := getInstance(t, tc.value).Value().LookupPath(ParsePath(tc.path))
+
+			var iter *Iterator
+			if v.IncompleteKind() == ListKind {
+				list, err := v.List()
+				if err != nil {
+					t.Fatal(err)
+				}
+				iter = &list
+			} else {
+				var err error
+				iter, err = v.Fields()
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var a []string
+			for iter.Next() {
+				a = append(a, fmt.Sprintf("%v:%d", iter.Selector(), iter.Index()))
+			}
+			if got := strings.Join(a, " "); got != tc.res {
+				t.Errorf("got %v; want %v", got, tc.res)
+			}
+		})
+	}
+}
+
 func TestAllFields(t *testing.T) {
 	testCases := []struct {
 		value string
@@ -2080,6 +2150,668 @@ func TestUnify(t *testing.T) {
 	}
 }
 
+func TestUnifySame(t *testing.T) {
+	testCases := []string{
+		`4`,
+		`int | *4`,
+		`>=3 & <10`,
+		`"foo" | "bar"`,
+		`{a: int, b: "foo"}`,
+		`{a: 1, b: a + 1}`,
+		`#D: {a: int}, x: #D`,
+		`close({a: 1})`,
+		`[1, ...int]`,
+		`{[string]: int, a: 1}`,
+		`{a?: string}`,
+		`{a: b, b: a}`,
+		`{a: 1 & 2}`,
+		`x: {a: int & "foo"}`,
+		`_|_`,
+		`1 & 2`,
+	}
+	for _, src := range testCases {
+		t.Run(src, func(t *testing.T) {
+			v := getInstance(t, src).Value()
+			for _, x := range []Value{v, v.LookupPath(ParsePath("x"))} {
+				if !x.Exists() {
+					continue
+				}
+				// Create a value with the same conjuncts but a different
+				// identity to trigger the structural check.
+				cp := *x.v
+				y := x
+				y.v = &cp
+
+				if got, want := sameVertex(x.v, y.v), x.Err() == nil; got != want {
+					t.Errorf("fast path: got %v; want %v", got, want)
+				}
+
+				fast := x.Unify(y)
+				slow := x.unify(y)
+
+				if got, want := fmt.Sprint(fast), fmt.Sprint(slow); got != want {
+					t.Errorf("value: got %v; want %v", got, want)
+				}
+				if got, want := fast.IncompleteKind(), slow.IncompleteKind(); got != want {
+					t.Errorf("kind: got %v; want %v", got, want)
+				}
+				if got, want := fmt.Sprint(fast.Validate()), fmt.Sprint(slow.Validate()); got != want {
+					t.Errorf("validate: got %v; want %v", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestUnifyChanged(t *testing.T) {
+	a := ParsePath("a")
+	b := ParsePath("b")
+	testCases := []struct {
+		value   string
+		pathA   Path
+		pathB   Path
+		want    string
+		changed bool
+		err     bool
+	}{{
+		value:   `a: string, b: "foo"`,
+		pathA:   a,
+		pathB:   b,
+		want:    `"foo"`,
+		changed: true,
+	}, {
+		value: `a: "foo", b: string`,
+		pathA: a,
+		pathB: b,
+		want:  `"foo"`,
+	}, {
+		value: `a: {x: int, y: 1}, b: {x: int}`,
+		pathA: a,
+		pathB: b,
+		want:  `{ x: int y: 1 }`,
+	}, {
+		value:   `a: {x: int}, b: {y: 1}`,
+		pathA:   a,
+		pathB:   b,
+		want:    `{ x: int y: 1 }`,
+		changed: true,
+	}, {
+		value: `a: {x: int} & {x: >0}, b: {x: >0}`,
+		pathA: a,
+		pathB: b,
+		want:  `{ x: >0 & int }`,
+	}, {
+		value: `a: *1 | int, b: int`,
+		pathA: a,
+		pathB: b,
+		want:  `*1 | int`,
+	}, {
+		value: `a: {x: 1}`,
+		pathA: a,
+		pathB: a,
+		want:  `{ x: 1 }`,
+	}, {
+		value:   `a: {x: int}, b: close({x: int})`,
+		pathA:   a,
+		pathB:   b,
+		want:    `{ x: int }`,
+		changed: true,
+	}, {
+		value: `a: close({x: int}), b: {x: int}`,
+		pathA: a,
+		pathB: b,
+		want:  `{ x: int }`,
+	}, {
+		value:   `a: s: {x: int}, b: s: close({x: int})`,
+		pathA:   a,
+		pathB:   b,
+		want:    `{ s: { x: int } }`,
+		changed: true,
+	}, {
+		value:   `a: {x: int}, b: #D, #D: {x: int}`,
+		pathA:   a,
+		pathB:   b,
+		want:    `{ x: int }`,
+		changed: true,
+	}, {
+		value:   `a: 1, b: 2`,
+		pathA:   a,
+		pathB:   b,
+		changed: true,
+		err:     true,
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			v := getInstance(t, tc.value).Value()
+			x := v.LookupPath(tc.pathA)
+			y := v.LookupPath(tc.pathB)
+			u, changed, err := x.UnifyChanged(y)
+			if (err != nil) != tc.err {
+				t.Fatalf("got error %v; want error: %v", err, tc.err)
+			}
+			if changed != tc.changed {
+				t.Errorf("changed: got %v; want %v", changed, tc.changed)
+			}
+			if tc.err {
+				return
+			}
+			if got := strings.Join(strings.Fields(fmt.Sprint(u)), " "); got != tc.want {
+				t.Errorf("got %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUnifyListMerge(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		strategy ListMergeStrategy
+		want     string
+	}{{
+		a:        `[1, 2]`,
+		b:        `[1, 2, 3]`,
+		strategy: ListStrict,
+		want:     `_|_ // a: incompatible list lengths (2 and 3)`,
+	}, {
+		a:        `[1, 2]`,
+		b:        `[1, 2, 3]`,
+		strategy: ListOverride,
+		want:     `[1,2,3]`,
+	}, {
+		a:        `[{a: 1}, 2, 3]`,
+		b:        `[{b: 1}]`,
+		strategy: ListOverride,
+		want:     `[{"b":1},2,3]`,
+	}, {
+		a:        `[1, 2]`,
+		b:        `[3]`,
+		strategy: ListOverride,
+		want:     `[3,2]`,
+	}, {
+		a:        `[1, 2, 3]`,
+		b:        `[4]`,
+		strategy: ListOverride,
+		want:     `[4,2,3]`,
+	}, {
+		a:        `[1, ...int]`,
+		b:        `["x", 2]`,
+		strategy: ListOverride,
+		want:     `["x",2]`,
+	}, {
+		a:        `[1, ...int]`,
+		b:        `["x", "y"]`,
+		strategy: ListOverride,
+		want:     `_|_ // a.1: conflicting values "y" and int (mismatched types string and int)`,
+	}, {
+		a:        `[...int]`,
+		b:        `[1, 2]`,
+		strategy: ListOverride,
+		want:     `[1,2]`,
+	}, {
+		a:        `[...int]`,
+		b:        `[1, "foo"]`,
+		strategy: ListOverride,
+		want:     `_|_ // a.1: conflicting values "foo" and int (mismatched types string and int)`,
+	}, {
+		a:        `[1, 2]`,
+		b:        `[1]`,
+		strategy: ListAppend,
+		want:     `[1,2,1]`,
+	}, {
+		a:        `[]`,
+		b:        `[]`,
+		strategy: ListAppend,
+		want:     `[]`,
+	}, {
+		a:        `{a: {b: [1], c: 1}, d: [1]}`,
+		b:        `{a: {b: [2]}, d: [1, 2]}`,
+		strategy: ListAppend,
+		want:     `{"a":{"b":[1,2],"c":1},"d":[1,1,2]}`,
+	}, {
+		a:        `{a: [1], [=~"^x"]: int}`,
+		b:        `{a: [2], x: "foo"}`,
+		strategy: ListAppend,
+		want:     `_|_ // a.x: conflicting values "foo" and int (mismatched types string and int)`,
+	}, {
+		a:        `{a: [1], b?: int}`,
+		b:        `{a: [2], b: "foo"}`,
+		strategy: ListAppend,
+		want:     `_|_ // a.b: conflicting values "foo" and int (mismatched types string and int)`,
+	}, {
+		a:        `close({a: [1]})`,
+		b:        `{a: [2], c: 1}`,
+		strategy: ListAppend,
+		want:     `_|_ // a.c: field not allowed`,
+	}, {
+		a:        `a: [1] @merge(list=append), b: [1]`,
+		b:        `a: [2], b: [1, 2]`,
+		strategy: ListOverride,
+		want:     `{"a":[1,2],"b":[1,2]}`,
+	}, {
+		a:        `a: {b: [1]} @merge(list=strict)`,
+		b:        `a: {b: [1, 2]}`,
+		strategy: ListOverride,
+		want:     `_|_ // a.a.b: incompatible list lengths (1 and 2)`,
+	}, {
+		a:        `a: [1] @merge(list=append)`,
+		b:        `a: [2] @merge(list=override)`,
+		strategy: ListStrict,
+		want:     `_|_ // a: conflicting list merge strategies append and override`,
+	}, {
+		a:        `a: [1] @merge(list=foo)`,
+		b:        `a: [2]`,
+		strategy: ListStrict,
+		want:     `_|_ // a: invalid list merge strategy "foo" in @merge attribute`,
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.a+" "+tc.strategy.String(), func(t *testing.T) {
+			v := getInstance(t, "a: {"+tc.a+"}\nb: {"+tc.b+"}").Value()
+			a := v.LookupPath(ParsePath("a"))
+			b := v.LookupPath(ParsePath("b"))
+			v = a.Unify(b, ListMerge(tc.strategy))
+			got := ""
+			if err := v.Validate(); err != nil {
+				got = "_|_ // " + err.Error()
+			} else {
+				b, err := v.MarshalJSON()
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = string(b)
+			}
+			if got != tc.want {
+				t.Errorf("got %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestOverlay(t *testing.T) {
+	testCases := []struct {
+		layers []string
+		want   string
+	}{{
+		layers: []string{`a: 1`},
+		want:   `{"a":1}`,
+	}, {
+		layers: []string{`a: 1, b: 2`, `a: 3`, `a: 4, c: 5`},
+		want:   `{"a":4,"b":2,"c":5}`,
+	}, {
+		layers: []string{`a: b: {c: 1, d: "x"}`, `a: b: {c: 2, e: true}`},
+		want:   `{"a":{"b":{"c":2,"d":"x","e":true}}}`,
+	}, {
+		// Values that unify are unified.
+		layers: []string{`a: int, b: *"x" | string`, `a: 3, b: "y"`},
+		want:   `{"a":3,"b":"y"}`,
+	}, {
+		layers: []string{`a: 3`, `a: int`},
+		want:   `{"a":3}`,
+	}, {
+		layers: []string{`a: >=5`, `a: 3`},
+		want:   `{"a":3}`,
+	}, {
+		// Kinds may change.
+		layers: []string{`a: {b: 1}`, `a: "str"`},
+		want:   `{"a":"str"}`,
+	}, {
+		layers: []string{`a: "str"`, `a: {b: 1}`},
+		want:   `{"a":{"b":1}}`,
+	}, {
+		layers: []string{`a: [1, {b: 1}]`, `a: [2, {c: 2}]`},
+		want:   `{"a":[2,{"b":1,"c":2}]}`,
+	}, {
+		layers: []string{`a: [1, 2, 3]`, `a: [4]`},
+		want:   `{"a":[4]}`,
+	}, {
+		layers: []string{`a: [1, 2, 3] @merge(list=override)`, `a: [4]`},
+		want:   `{"a":[4,2,3]}`,
+	}, {
+		layers: []string{`a: [{b: 1}, 2] @merge(list=override)`, `a: [{c: 2}]`},
+		want:   `{"a":[{"c":2},2]}`,
+	}, {
+		layers: []string{`a: [1, 2]`, `a: [3] @merge(list=append)`},
+		want:   `{"a":[1,2,3]}`,
+	}, {
+		layers: []string{`a: [1] @merge(list=foo)`, `a: [2]`},
+		want:   `_|_ // invalid list merge strategy "foo" in @merge attribute`,
+	}, {
+		layers: []string{`a: 1`, `a: 1 & 2`},
+		want:   `_|_ // a: conflicting values 2 and 1`,
+	}}
+	for _, tc := range testCases {
+		t.Run(strings.Join(tc.layers, ";"), func(t *testing.T) {
+			var layers []Value
+			for _, l := range tc.layers {
+				layers = append(layers, getInstance(t, l).Value())
+			}
+			got := ""
+			v, err := Overlay(layers...)
+			if err == nil {
+				err = v.Validate(Concrete(true))
+			}
+			if err != nil {
+				got = "_|_ // " + err.Error()
+			} else {
+				b, err := v.MarshalJSON()
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = string(b)
+			}
+			if got != tc.want {
+				t.Errorf("got %v; want %v", got, tc.want)
+			}
+		})
+	}
+
+	if _, err := Overlay(); err == nil {
+		t.Error("expected error for no layers")
+	}
+}
+
+func TestOverlayConstraints(t *testing.T) {
+	testCases := []struct {
+		layers []string
+		unify  string
+		err    string
+	}{{
+		layers: []string{`x: [string]: int`, `x: a: 1`},
+		unify:  `x: b: 2`,
+	}, {
+		layers: []string{`x: [string]: int`, `x: a: 1`},
+		unify:  `x: b: "str"`,
+		err:    `x.b: conflicting values "str" and int (mismatched types string and int)`,
+	}, {
+		layers: []string{`x: a: 1`, `x: [string]: int`},
+		unify:  `x: b: "str"`,
+		err:    `x.b: conflicting values "str" and int (mismatched types string and int)`,
+	}, {
+		layers: []string{`x: {a: 1, opt?: int}`, `x: a: 2`},
+		unify:  `x: opt: 3`,
+	}, {
+		layers: []string{`x: {a: 1, opt?: int}`, `x: a: 2`},
+		unify:  `x: opt: "str"`,
+		err:    `x.opt: conflicting values "str" and int (mismatched types string and int)`,
+	}}
+	for _, tc := range testCases {
+		t.Run(strings.Join(tc.layers, ";"), func(t *testing.T) {
+			var layers []Value
+			for _, l := range tc.layers {
+				layers = append(layers, getInstance(t, l).Value())
+			}
+			v, err := Overlay(layers...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = v.Unify(getInstance(t, tc.unify).Value()).Validate()
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if got != tc.err {
+				t.Errorf("got error %q; want %q", got, tc.err)
+			}
+		})
+	}
+
+	v, err := Overlay(
+		getInstance(t, `x: {a: 1, opt?: int}`).Value(),
+		getInstance(t, `x: a: 2`).Value())
+	if err != nil {
+		t.Fatal(err)
+	}
+	iter, err := v.LookupPath(ParsePath("x")).Fields(Optional(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var labels []string
+	for iter.Next() {
+		labels = append(labels, iter.Label())
+	}
+	if got, want := strings.Join(labels, ","), "a,opt"; got != want {
+		t.Errorf("got fields %s; want %s", got, want)
+	}
+}
+
+func TestCompatibility(t *testing.T) {
+	testCases := []struct {
+		old, new string
+		level    CompatLevel
+		err      string
+	}{{
+		old:   `{a: int, b?: string}`,
+		new:   `{a: int, b?: string}`,
+		level: FullyCompatible,
+	}, {
+		// The type of b is constrained in new.
+		old:   `{a: int}`,
+		new:   `{a: int, b?: string}`,
+		level: ForwardCompatible,
+		err:   "new.b: optional field added",
+	}, {
+		old:   `close({a: int})`,
+		new:   `close({a: int, b?: string})`,
+		level: BackwardCompatible,
+		err:   "new.b: optional field added",
+	}, {
+		old:   `{a: int}`,
+		new:   `{a: int, b: string}`,
+		level: ForwardCompatible,
+		err:   "new.b: required field added",
+	}, {
+		old:   `{a: int, b: string}`,
+		new:   `{a: int}`,
+		level: BackwardCompatible,
+		err:   "old.b: field removed",
+	}, {
+		old:   `{a: int}`,
+		new:   `{a: int & >=0}`,
+		level: ForwardCompatible,
+		err:   "new.a: narrowed type from int to >=0 & int",
+	}, {
+		old:   `{a: x: "a" | "b"}`,
+		new:   `{a: x: "a" | "b" | "c"}`,
+		level: BackwardCompatible,
+		err:   `new.a.x: widened type from "a" | "b" to "a" | "b" | "c"`,
+	}, {
+		old:   `{a: int}`,
+		new:   `{a: string}`,
+		level: Incompatible,
+		err:   "new.a: changed type from int to string",
+	}, {
+		old:   `{a?: int}`,
+		new:   `{a: int}`,
+		level: ForwardCompatible,
+		err:   "new.a: field made required",
+	}, {
+		old:   `{#D: {a: int}, d: #D}`,
+		new:   `{#D: {a: int, b: int}, d: #D}`,
+		level: Incompatible,
+		err:   "new.#D.b: required field added (and 1 more errors)",
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.old+" -> "+tc.new, func(t *testing.T) {
+			v := getInstance(t, "old: "+tc.old+"\nnew: "+tc.new).Value()
+			level, err := Compatibility(v.LookupPath(ParsePath("old")), v.LookupPath(ParsePath("new")))
+			if level != tc.level {
+				t.Errorf("level: got %v; want %v", level, tc.level)
+			}
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if got != tc.err {
+				t.Errorf("error: got %q; want %q", got, tc.err)
+			}
+		})
+	}
+}
+
+func TestDefinitions(t *testing.T) {
+	inst := getInstance(t, `
+	// Foo is a foo.
+	#Foo: {
+		#Nested: int
+		a:       int
+	} @schema(foo)
+
+	x: 1
+
+	_#Hidden: string
+
+	// Bar has
+	// two lines.
+	#Bar: #Foo & {a: 1} @schema(bar) @other()
+	`)
+
+	type def struct {
+		name   string
+		hidden bool
+		doc    string
+		attrs  []string
+		line   int
+	}
+	var got []def
+	for _, d := range inst.Definitions() {
+		x := def{name: d.Name, hidden: d.Hidden, line: d.Pos.Line()}
+		for _, cg := range d.Doc {
+			x.doc += cg.Text()
+		}
+		for _, a := range d.Attrs {
+			x.attrs = append(x.attrs, a.Name())
+		}
+		got = append(got, x)
+	}
+	want := []def{
+		{name: "#Foo", doc: "Foo is a foo.\n", attrs: []string{"schema"}, line: 3},
+		{name: "_#Hidden", hidden: true, line: 10},
+		{name: "#Bar", doc: "Bar has\ntwo lines.\n", attrs: []string{"schema", "other"}, line: 14},
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(def{})); diff != "" {
+		t.Error(diff)
+	}
+
+	nested := inst.Definitions()[0].Value.LookupPath(ParsePath("#Nested"))
+	if k := nested.IncompleteKind(); k != IntKind {
+		t.Errorf("nested definition: got kind %v; want int", k)
+	}
+}
+
+func TestFromComprehension(t *testing.T) {
+	v := getInstance(t, `
+	l: ["a", "b"]
+	s: c: 1
+
+	static: 1
+	for k in l {
+		"\(k)": {x: 1, ref: s}
+	}
+	if true {
+		cond: 2
+	}
+	a: y: 3
+	both: int
+	if true {
+		both: 4
+	}
+	`).Value()
+
+	var got []string
+	var walk func(v Value, path string)
+	walk = func(v Value, path string) {
+		iter, _ := v.Fields()
+		for iter.Next() {
+			p := path + iter.Selector().String()
+			got = append(got, fmt.Sprintf("%s: %v", p, iter.Value().FromComprehension()))
+			walk(iter.Value(), p+".")
+		}
+	}
+	walk(v, "")
+
+	want := []string{
+		"l: false",
+		"s: false",
+		"s.c: false",
+		"static: false",
+		"a: true",
+		"a.y: false",
+		"a.x: true",
+		"a.ref: true",
+		"a.ref.c: true",
+		"cond: true",
+		"b: true",
+		"b.x: true",
+		"b.ref: true",
+		"b.ref.c: true",
+		"both: true",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestApply(t *testing.T) {
+	testCases := []struct {
+		schema string
+		data   string
+		want   string
+		err    string
+	}{{
+		schema: `{a: int, b: *"foo" | string, c?: int}`,
+		data:   `{a: 1}`,
+		want:   `{a: 1, b: "foo"}`,
+	}, {
+		schema: `{a: int, b: *"foo" | string}`,
+		data:   `{a: 1, b: "bar"}`,
+		want:   `{a: 1, b: "bar"}`,
+	}, {
+		schema: `{#D: {x: *1 | int}, d: #D, l: *[1, 2] | [...int]}`,
+		data:   `{}`,
+		want:   `{d: {x: 1}, l: [1, 2]}`,
+	}, {
+		schema: `{a: int, b: {c: string, d: *1 | 2}}`,
+		data:   `{}`,
+		err:    "a: incomplete value int\nb.c: incomplete value string",
+	}, {
+		schema: `close({a: int})`,
+		data:   `{a: 1, b: 2}`,
+		err:    "b: field not allowed",
+	}, {
+		schema: `{a: *1 | 2}`,
+		data:   `{a: "foo"}`,
+		err: `a: 2 errors in empty disjunction:
+a: disjunct 1: conflicting values "foo" and 1 (mismatched types string and int)
+a: disjunct 2: conflicting values "foo" and 2 (mismatched types string and int)`,
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.schema, func(t *testing.T) {
+			v := getInstance(t, "schema: "+tc.schema+"\ndata: "+tc.data).Value()
+			schema := v.LookupPath(ParsePath("schema"))
+			data := v.LookupPath(ParsePath("data"))
+
+			w, err := schema.Apply(data)
+			if err != nil {
+				var msgs []string
+				for _, e := range errors.Errors(err) {
+					msgs = append(msgs, strings.TrimPrefix(e.Error(), "schema."))
+				}
+				if got := strings.Join(msgs, "\n"); got != tc.err {
+					t.Errorf("error: got %q; want %q", got, tc.err)
+				}
+				return
+			}
+			if tc.err != "" {
+				t.Fatalf("got no error; want %q", tc.err)
+			}
+			got := fmt.Sprint(w)
+			want := fmt.Sprint(getInstance(t, tc.want).Value())
+			if got != want {
+				t.Errorf("got %s; want %s", got, want)
+			}
+		})
+	}
+}
+
 func TestEquals(t *testing.T) {
 	testCases := []struct {
 		a, b string
@@ -2279,6 +3011,33 @@ func TestValidate(t *testing.T) {
 			"variables"?: #variables
 		}
 		`,
+	}, {
+		desc: "within max depth",
+		in: `
+		c: d: e: f: 5
+		`,
+		opts: []Option{MaxDepth(4)},
+	}, {
+		desc: "documented definitions",
+		in: `
+		// A is documented.
+		#A: {
+			b: int
+			#C: string @doc("C is documented.")
+		}
+		d: 1
+		`,
+		opts: []Option{RequireDocs(false)},
+	}, {
+		desc: "undocumented fields",
+		in: `
+		// A is documented.
+		#A: {
+			b: int
+		}
+		`,
+		opts: []Option{RequireDocs(true)},
+		err:  true,
 	}}
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -2294,6 +3053,82 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestValidateMaxDepth(t *testing.T) {
+	r := Runtime{}
+	inst, err := r.Parse("validate", `c: d: e: f: 5`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = inst.Value().Validate(MaxDepth(3))
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if got, want := err.Error(), "c.d.e.f: maximum depth of 3 exceeded"; got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+	if got, want := errors.Path(err), []string{"c", "d", "e", "f"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got path %v; want %v", got, want)
+	}
+}
+
+func TestValidateRequireDocs(t *testing.T) {
+	const in = `
+	// A is documented.
+	#A: {
+		b: int
+		#C: {
+			d: string
+		}
+	}
+	#A: {
+		// b is documented in another conjunct.
+		b: int
+	}
+	#E: {
+		f?: int @doc("f is documented.")
+		g: [...#A]
+	}
+	h: 1
+	`
+	testCases := []struct {
+		fields bool
+		out    string
+	}{{
+		fields: false,
+		out: `#A.#C: missing documentation:
+    in:5:3
+#E: missing documentation:
+    in:13:2
+`,
+	}, {
+		fields: true,
+		out: `#A.#C: missing documentation:
+    in:5:3
+#A.#C.d: missing documentation:
+    in:6:4
+#E: missing documentation:
+    in:13:2
+#E.g: missing documentation:
+    in:15:3
+h: missing documentation:
+    in:17:2
+`,
+	}}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprint(tc.fields), func(t *testing.T) {
+			r := Runtime{}
+			inst, err := r.Parse("in", in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = inst.Value().Validate(RequireDocs(tc.fields))
+			if got := errors.Details(err, nil); got != tc.out {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tc.out)
+			}
+		})
+	}
+}
+
 func TestPath(t *testing.T) {
 	config := `
 	a: b: c: 5
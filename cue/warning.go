@@ -0,0 +1,121 @@
+// Copyright 2022 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cue
+
+import (
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/token"
+	"cuelang.org/go/internal"
+	"cuelang.org/go/internal/core/adt"
+)
+
+// Warnings reports non-fatal problems found in v and the values it contains.
+// Warnings never affect the evaluated value: a value with warnings is not an
+// error.
+//
+// Currently, a warning is reported for each field that is marked as
+// deprecated with a @deprecated attribute, for instance
+//
+//	#Schema: {
+//		oldName?: string @deprecated("use newName")
+//		newName?: string
+//	}
+//
+// and that is set by a declaration other than the one marking it as
+// deprecated. Optional fields and fields of referenced definitions declare a
+// schema and do not count as setting a field. The warning includes the
+// message passed to the attribute, if any, and is positioned at the field that
+// sets the value.
+func (v Value) Warnings() errors.Error {
+	if v.v == nil {
+		return nil
+	}
+	v.v.Finalize(v.ctx())
+	var errs errors.Error
+	v.appendWarnings(&errs)
+	return errs
+}
+
+func (v Value) appendWarnings(errs *errors.Error) {
+	if msg, pos, ok := deprecatedUse(v.v); ok {
+		text := "field is deprecated"
+		if msg != "" {
+			text += ": " + msg
+		}
+		*errs = errors.Append(*errs, v.toErr(&adt.Bottom{
+			Err: errors.Newf(pos, "%s", text),
+		}))
+	}
+	for _, arc := range v.v.Arcs {
+		if arc.Status() == 0 || arc.BaseValue == nil {
+			continue
+		}
+		makeChildValue(v, arc).appendWarnings(errs)
+	}
+}
+
+// deprecatedUse reports whether x is declared with a @deprecated attribute and
+// is also set by a conjunct without this attribute. It returns the deprecation
+// message and the position of the first such conjunct.
+//
+// Optional fields and fields introduced through a reference to a definition
+// only declare the schema of x and are not considered uses. A field of a
+// definition that sets x is reported for the definition itself.
+func deprecatedUse(x *adt.Vertex) (msg string, pos token.Pos, ok bool) {
+	deprecated := false
+	used := false
+	for _, c := range x.Conjuncts {
+		m, isDeprecated := deprecation(c.Field())
+		switch {
+		case isDeprecated && !deprecated:
+			deprecated = true
+			msg = m
+		case !isDeprecated && !used && isUse(c):
+			used = true
+			if src := c.Source(); src != nil {
+				pos = src.Pos()
+			}
+		}
+	}
+	return msg, pos, deprecated && used
+}
+
+// isUse reports whether c sets a value, rather than declaring a schema.
+func isUse(c adt.Conjunct) bool {
+	if _, ok := c.Field().(*adt.OptionalField); ok {
+		return false
+	}
+	return !c.CloseInfo.IsInOneOf(adt.DefinitionSpan)
+}
+
+// deprecation reports whether n is a field with a @deprecated attribute and
+// the message passed to it.
+func deprecation(n adt.Node) (msg string, ok bool) {
+	f, _ := n.Source().(*ast.Field)
+	if f == nil {
+		return "", false
+	}
+	for _, a := range f.Attrs {
+		key, body := a.Split()
+		if key != "deprecated" {
+			continue
+		}
+		attr := internal.ParseAttrBody(a.Pos(), body)
+		msg, _ = attr.String(0)
+		return msg, true
+	}
+	return "", false
+}
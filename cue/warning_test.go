@@ -0,0 +1,103 @@
+// Copyright 2022 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cue_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/cue/errors"
+)
+
+func TestWarnings(t *testing.T) {
+	testCases := []struct {
+		name string
+		in   string
+		want string
+	}{{
+		name: "unused",
+		in: `
+		#S: {
+			old?: int @deprecated("use new")
+			new?: int
+		}
+		x: #S & {new: 1}
+		`,
+	}, {
+		name: "used",
+		in: `
+		#S: {
+			old?: int @deprecated("use new")
+			new?: int
+		}
+		x: #S & {old: 1}
+		`,
+		want: "x.old: field is deprecated: use new (6:12)",
+	}, {
+		name: "no message",
+		in: `
+		#S: old?: int @deprecated()
+		x: #S
+		x: old: 1
+		`,
+		want: "x.old: field is deprecated (4:6)",
+	}, {
+		name: "regular field with default",
+		in: `
+		#S: old: *1 | int @deprecated("use new")
+		x: #S
+		`,
+	}, {
+		name: "list elements",
+		in: `
+		#S: old?: int @deprecated("use new")
+		l: [...#S]
+		l: [{}, {old: 1}, {old: 2}]
+		`,
+		want: "l.1.old: field is deprecated: use new (4:12)\n" +
+			"l.2.old: field is deprecated: use new (4:22)",
+	}, {
+		name: "schema redeclarations",
+		in: `
+		#S: old?: int @deprecated("use new")
+		#S: old?: int
+		#T: #S & {old?: <5}
+		#U: #S & {old: int}
+		x: #T
+		y: #U
+		z: #T & {old: 1}
+		`,
+		want: "#U.old: field is deprecated: use new (5:13)\n" +
+			"z.old: field is deprecated: use new (8:12)",
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := cuecontext.New().CompileString(tc.in)
+			if err := v.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, err := range errors.Errors(v.Warnings()) {
+				pos := err.Position()
+				got = append(got, fmt.Sprintf("%v (%d:%d)", err, pos.Line(), pos.Column()))
+			}
+			if got := strings.Join(got, "\n"); got != tc.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
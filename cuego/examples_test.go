@@ -45,8 +45,8 @@ func ExampleComplete_structTag() {
 	// completed: cuego_test.Sum{A:1, B:5, C:6} (err: <nil>)
 	// completed: cuego_test.Sum{A:2, B:6, C:8} (err: <nil>)
 	// 2 errors in empty disjunction:
-	// conflicting values null and {A:2,B:3,C:8} (mismatched types null and struct)
-	// A: conflicting values 5 and 2
+	// disjunct 1: conflicting values null and {A:2,B:3,C:8} (mismatched types null and struct)
+	// A: disjunct 2: conflicting values 5 and 2
 }
 
 func ExampleConstrain() {
@@ -98,11 +98,11 @@ func ExampleConstrain() {
 	// error: nil
 	// validate: nil
 	// validate: 2 errors in empty disjunction:
-	// conflicting values null and {Filename:"foo.json",MaxCount:12,MinCount:39} (mismatched types null and struct)
-	// MinCount: invalid value 39 (out of bound <=12)
+	// disjunct 1: conflicting values null and {Filename:"foo.json",MaxCount:12,MinCount:39} (mismatched types null and struct)
+	// MinCount: disjunct 2: invalid value 39 (out of bound <=12)
 	// validate: 2 errors in empty disjunction:
-	// conflicting values null and {Filename:"foo.jso",MaxCount:120,MinCount:39} (mismatched types null and struct)
-	// Filename: invalid value "foo.jso" (out of bound =~".json$")
+	// disjunct 1: conflicting values null and {Filename:"foo.jso",MaxCount:120,MinCount:39} (mismatched types null and struct)
+	// Filename: disjunct 2: invalid value "foo.jso" (out of bound =~".json$")
 }
 
 func errMsg(err error) string {
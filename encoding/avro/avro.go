@@ -0,0 +1,80 @@
+// Copyright 2022 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package avro converts Avro schemas to CUE.
+//
+// Avro schemas are JSON documents, so any of the supported encodings that can
+// represent JSON can be used as a source.
+//
+// # Mapping
+//
+// Named types (records, enums, and fixed types) are converted to definitions
+// named after the unqualified name of the type. References to named types
+// become references to the corresponding definition, which allows recursive
+// types to be expressed naturally. Other types map as follows:
+//
+//	null, boolean          null, bool
+//	int, long              int32, int64
+//	float, double          float32, float64
+//	bytes, string          bytes, string
+//	record                 closed struct
+//	enum                   disjunction of strings
+//	array, map             [...T], {[string]: T}
+//	union                  disjunction
+//
+// Logical types further constrain the underlying type where possible. For
+// instance, a decimal maps to a number bounded by its precision and scale and
+// a uuid maps to a string matching the canonical UUID format. Dates and
+// timestamps are bounded by the range of instants that can be represented in
+// RFC 3339 format. Unknown logical types are ignored, in which case the
+// underlying type is used.
+//
+// Record fields named after predeclared identifiers, such as string, have
+// quoted labels so that they do not shadow the identifiers within the record.
+package avro
+
+import (
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
+)
+
+// Extract converts an Avro schema into an equivalent CUE representation.
+//
+// Named types are converted to definitions. If the top-level schema is not
+// a named type, the result embeds the corresponding CUE type.
+func Extract(data cue.InstanceOrValue, cfg *Config) (f *ast.File, err error) {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	d := &decoder{
+		cfg:   cfg,
+		names: map[string]string{},
+		defs:  map[string]string{},
+	}
+
+	f = d.decode(data.Value())
+	if d.errs != nil {
+		return nil, d.errs
+	}
+	return f, nil
+}
+
+// A Config configures an Avro schema decoding.
+type Config struct {
+	// PkgName is the package name of the generated file. No package clause
+	// is generated if it is empty.
+	PkgName string
+
+	_ struct{} // prohibit casting from different type.
+}
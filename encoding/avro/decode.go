@@ -0,0 +1,440 @@
+// Copyright 2022 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package avro
+
+import (
+	"fmt"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/token"
+	"cuelang.org/go/internal"
+)
+
+// uuidPattern matches the canonical textual representation of a UUID.
+const uuidPattern = "^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$"
+
+// primitives maps Avro primitive type names to the corresponding CUE types.
+// The null type is handled separately, as it maps to a literal.
+var primitives = map[string]string{
+	"boolean": "bool",
+	"int":     "int32",
+	"long":    "int64",
+	"float":   "float32",
+	"double":  "float64",
+	"bytes":   "bytes",
+	"string":  "string",
+}
+
+// predeclared holds the predeclared identifiers that may be used in the
+// generated CUE.
+var predeclared = map[string]bool{
+	"bool":    true,
+	"int32":   true,
+	"int64":   true,
+	"float32": true,
+	"float64": true,
+	"bytes":   true,
+	"string":  true,
+	"number":  true,
+}
+
+// A decoder converts an Avro schema to CUE.
+type decoder struct {
+	cfg  *Config
+	errs errors.Error
+
+	// names maps the full names of named types to the names of their
+	// definitions.
+	names map[string]string
+
+	// defs maps definition names to the full name of the type they define.
+	defs map[string]string
+
+	// decls holds the definitions of named types in order of declaration.
+	decls []ast.Decl
+}
+
+func (d *decoder) decode(v cue.Value) *ast.File {
+	f := &ast.File{}
+
+	if pkgName := d.cfg.PkgName; pkgName != "" {
+		pkg := &ast.Package{Name: ast.NewIdent(pkgName)}
+		f.Decls = append(f.Decls, pkg)
+	}
+
+	expr := d.schema(v, "")
+	if !isNamed(v) {
+		f.Decls = append(f.Decls, &ast.EmbedDecl{Expr: expr})
+	}
+	f.Decls = append(f.Decls, d.decls...)
+
+	return f
+}
+
+func (d *decoder) errf(n cue.Value, format string, args ...interface{}) ast.Expr {
+	d.errs = errors.Append(d.errs, errors.Newf(n.Pos(), format, args...))
+	return &ast.BadExpr{From: n.Pos()}
+}
+
+// isNamed reports whether v defines a named type.
+func isNamed(v cue.Value) bool {
+	if v.Kind() != cue.StructKind {
+		return false
+	}
+	t, _ := lookup(v, "type").String()
+	switch t {
+	case "record", "error", "enum", "fixed":
+		return true
+	}
+	return false
+}
+
+func lookup(v cue.Value, name string) cue.Value {
+	return v.LookupPath(cue.MakePath(cue.Str(name)))
+}
+
+// schema converts the Avro schema n, defined within namespace ns, to CUE.
+func (d *decoder) schema(n cue.Value, ns string) ast.Expr {
+	switch n.Kind() {
+	case cue.StringKind:
+		name, _ := n.String()
+		if name == "null" {
+			return ast.NewNull()
+		}
+		if t, ok := primitives[name]; ok {
+			return ast.NewIdent(t)
+		}
+		return d.ref(n, name, ns)
+
+	case cue.ListKind:
+		return d.union(n, ns)
+
+	case cue.StructKind:
+		return d.object(n, ns)
+
+	default:
+		return d.errf(n, "invalid schema %v", n)
+	}
+}
+
+// ref returns a reference to the definition of the named type with the given
+// name.
+func (d *decoder) ref(n cue.Value, name, ns string) ast.Expr {
+	if !strings.Contains(name, ".") && ns != "" {
+		if def, ok := d.names[ns+"."+name]; ok {
+			return ast.NewIdent(def)
+		}
+	}
+	if def, ok := d.names[name]; ok {
+		return ast.NewIdent(def)
+	}
+	return d.errf(n, "undefined type %q", name)
+}
+
+func (d *decoder) union(n cue.Value, ns string) ast.Expr {
+	var a []ast.Expr
+	for i, _ := n.List(); i.Next(); {
+		v := i.Value()
+		if v.Kind() == cue.ListKind {
+			d.errf(v, "unions may not immediately contain other unions")
+			continue
+		}
+		a = append(a, d.schema(v, ns))
+	}
+	if len(a) == 0 {
+		return d.errf(n, "empty union")
+	}
+	return ast.NewBinExpr(token.OR, a...)
+}
+
+func (d *decoder) object(n cue.Value, ns string) ast.Expr {
+	t := lookup(n, "type")
+	if !t.Exists() {
+		return d.errf(n, "missing type")
+	}
+
+	switch typ, _ := t.String(); typ {
+	case "record", "error":
+		return d.record(n, ns)
+
+	case "enum":
+		return d.enum(n, ns)
+
+	case "fixed":
+		return d.fixed(n, ns)
+
+	case "array":
+		elem := d.schema(lookup(n, "items"), ns)
+		return ast.NewList(&ast.Ellipsis{Type: elem})
+
+	case "map":
+		value := d.schema(lookup(n, "values"), ns)
+		return ast.NewStruct(&ast.Field{
+			Label: ast.NewList(ast.NewIdent("string")),
+			Value: value,
+		})
+
+	default:
+		if _, ok := primitives[typ]; ok {
+			if x := d.logicalType(n, typ); x != nil {
+				return x
+			}
+		}
+		return d.schema(t, ns)
+	}
+}
+
+// logicalType returns the CUE type for the logical type annotated on n, which
+// has the underlying type typ, or nil if n has no logical type or if it is
+// not valid for typ.
+func (d *decoder) logicalType(n cue.Value, typ string) ast.Expr {
+	logical, err := lookup(n, "logicalType").String()
+	if err != nil {
+		return nil
+	}
+	switch {
+	case logical == "decimal" && (typ == "bytes" || typ == "fixed"):
+		return d.decimal(n)
+
+	case logical == "uuid" && typ == "string":
+		return ast.NewBinExpr(token.AND,
+			ast.NewIdent("string"),
+			&ast.UnaryExpr{Op: token.MAT, X: ast.NewString(uuidPattern)})
+
+	case logical == "time-millis" && typ == "int":
+		return timeOfDay("int32", "86400000")
+
+	case logical == "time-micros" && typ == "long":
+		return timeOfDay("int64", "86400000000")
+
+	case logical == "date" && typ == "int":
+		return timeRange("int32", "-719162", "2932896")
+
+	case (logical == "timestamp-millis" || logical == "local-timestamp-millis") &&
+		typ == "long":
+		return timeRange("int64", "-62135596800000", "253402300799999")
+
+	case (logical == "timestamp-micros" || logical == "local-timestamp-micros") &&
+		typ == "long":
+		return timeRange("int64", "-62135596800000000", "253402300799999999")
+	}
+	return nil
+}
+
+// timeRange returns the type for a number of units since the Unix epoch,
+// bounded by the first and last instant representable in RFC 3339 format,
+// which are 0001-01-01T00:00:00Z and 9999-12-31T23:59:59.999999999Z.
+func timeRange(typ, min, max string) ast.Expr {
+	return ast.NewBinExpr(token.AND,
+		ast.NewIdent(typ),
+		&ast.UnaryExpr{Op: token.GEQ, X: ast.NewLit(token.INT, min)},
+		&ast.UnaryExpr{Op: token.LEQ, X: ast.NewLit(token.INT, max)})
+}
+
+// timeOfDay returns the type for a time of day with the given resolution,
+// bounded by the number of units in a day.
+func timeOfDay(typ, day string) ast.Expr {
+	return ast.NewBinExpr(token.AND,
+		ast.NewIdent(typ),
+		&ast.UnaryExpr{Op: token.GEQ, X: ast.NewLit(token.INT, "0")},
+		&ast.UnaryExpr{Op: token.LSS, X: ast.NewLit(token.INT, day)})
+}
+
+// decimal returns the type for a decimal logical type: a number of which the
+// magnitude is bounded by the number of digits before the decimal point.
+// It returns nil if the precision or scale is invalid.
+func (d *decoder) decimal(n cue.Value) ast.Expr {
+	precision, err := lookup(n, "precision").Int64()
+	if err != nil || precision <= 0 {
+		return nil
+	}
+	var scale int64
+	if s := lookup(n, "scale"); s.Exists() {
+		if scale, err = s.Int64(); err != nil || scale < 0 || scale > precision {
+			return nil
+		}
+	}
+	bound := fmt.Sprintf("1e%d", precision-scale)
+	return ast.NewBinExpr(token.AND,
+		ast.NewIdent("number"),
+		&ast.UnaryExpr{Op: token.GTR, X: &ast.UnaryExpr{
+			Op: token.SUB,
+			X:  ast.NewLit(token.FLOAT, bound),
+		}},
+		&ast.UnaryExpr{Op: token.LSS, X: ast.NewLit(token.FLOAT, bound)})
+}
+
+// define registers the named type defined by n and adds a definition for it.
+// It returns the definition and the namespace of the named type, or nil if
+// the type could not be defined.
+func (d *decoder) define(n cue.Value, ns string) (f *ast.Field, newNS string) {
+	name, err := lookup(n, "name").String()
+	if err != nil {
+		d.errf(n, "missing name for named type")
+		return nil, ""
+	}
+
+	fullName := name
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		ns = name[:i]
+		name = name[i+1:]
+	} else {
+		if s := lookup(n, "namespace"); s.Exists() {
+			ns, _ = s.String()
+		}
+		if ns != "" {
+			fullName = ns + "." + name
+		}
+	}
+
+	if _, ok := d.names[fullName]; ok {
+		d.errf(n, "type %q redefined", fullName)
+		return nil, ""
+	}
+	def := "#" + name
+	if other, ok := d.defs[def]; ok {
+		d.errf(n, "type %q conflicts with type %q", fullName, other)
+		return nil, ""
+	}
+	d.names[fullName] = def
+	d.defs[def] = fullName
+
+	f = &ast.Field{Label: ast.NewIdent(def)}
+	addDoc(f, n)
+	d.decls = append(d.decls, f)
+	return f, ns
+}
+
+func (d *decoder) record(n cue.Value, ns string) ast.Expr {
+	f, ns := d.define(n, ns)
+	if f == nil {
+		return &ast.BadExpr{From: n.Pos()}
+	}
+
+	// The definition is registered before the fields are converted, so that
+	// fields may refer to the enclosing record.
+	s := &ast.StructLit{}
+	f.Value = s
+
+	fields := lookup(n, "fields")
+	iter, err := fields.List()
+	if err != nil {
+		d.errf(n, "record %s must have a list of fields", defName(f))
+	}
+
+	for iter.Next() {
+		v := iter.Value()
+		name, err := lookup(v, "name").String()
+		if err != nil {
+			d.errf(v, "missing name for field")
+			continue
+		}
+		value := d.schema(lookup(v, "type"), ns)
+		if dv := lookup(v, "default"); dv.Exists() {
+			a := []ast.Expr{&ast.UnaryExpr{
+				Op: token.MUL,
+				X:  dv.Syntax(cue.Final()).(ast.Expr),
+			}}
+			value = ast.NewBinExpr(token.OR, append(a, disjuncts(value)...)...)
+		}
+		field := &ast.Field{Label: label(name), Value: value}
+		addDoc(field, v)
+		s.Elts = append(s.Elts, field)
+	}
+
+	return ast.NewIdent(defName(f))
+}
+
+func (d *decoder) enum(n cue.Value, ns string) ast.Expr {
+	f, _ := d.define(n, ns)
+	if f == nil {
+		return &ast.BadExpr{From: n.Pos()}
+	}
+
+	var a []ast.Expr
+	for i, _ := lookup(n, "symbols").List(); i.Next(); {
+		s, err := i.Value().String()
+		if err != nil {
+			d.errf(i.Value(), "enum symbol must be a string")
+			continue
+		}
+		a = append(a, ast.NewString(s))
+	}
+	if len(a) == 0 {
+		f.Value = d.errf(n, "enum %s must have at least one symbol", defName(f))
+	} else {
+		f.Value = ast.NewBinExpr(token.OR, a...)
+	}
+
+	return ast.NewIdent(defName(f))
+}
+
+func (d *decoder) fixed(n cue.Value, ns string) ast.Expr {
+	f, _ := d.define(n, ns)
+	if f == nil {
+		return &ast.BadExpr{From: n.Pos()}
+	}
+
+	size, err := lookup(n, "size").Int64()
+	if err != nil || size < 0 {
+		d.errf(n, "fixed %s must have a non-negative size", defName(f))
+	}
+	f.Attrs = append(f.Attrs, internal.NewAttr("avro", fmt.Sprintf("size=%d", size)))
+
+	f.Value = ast.NewIdent("bytes")
+	if x := d.logicalType(n, "fixed"); x != nil {
+		f.Value = x
+	}
+
+	return ast.NewIdent(defName(f))
+}
+
+// disjuncts returns the disjuncts of x, which is a single element if x is not
+// a disjunction.
+func disjuncts(x ast.Expr) []ast.Expr {
+	if b, ok := x.(*ast.BinaryExpr); ok && b.Op == token.OR {
+		return append(disjuncts(b.X), disjuncts(b.Y)...)
+	}
+	return []ast.Expr{x}
+}
+
+func defName(f *ast.Field) string {
+	return f.Label.(*ast.Ident).Name
+}
+
+// label returns the label for a record field with the given name. Names that
+// would otherwise denote hidden fields are quoted, as are names of predeclared
+// identifiers: a quoted label does not shadow the identifier, so that, for
+// instance, a field named string does not capture references to the string
+// type within its record.
+func label(name string) ast.Label {
+	if ast.IsValidIdent(name) && !strings.HasPrefix(name, "_") && !predeclared[name] {
+		return ast.NewIdent(name)
+	}
+	return ast.NewString(name)
+}
+
+func addDoc(n ast.Node, v cue.Value) {
+	doc, err := lookup(v, "doc").String()
+	if err != nil {
+		return
+	}
+	if cg := internal.NewComment(true, doc); cg != nil {
+		ast.SetComments(n, []*ast.CommentGroup{cg})
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2022 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package avro
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/txtar"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/format"
+	"cuelang.org/go/encoding/json"
+	"cuelang.org/go/internal/cuetest"
+)
+
+// TestExtract reads the testdata/*.txtar files, converts the contained
+// Avro schema to CUE and compares it against the output.
+//
+// Set CUE_UPDATE=1 to update test files with the corresponding output.
+func TestExtract(t *testing.T) {
+	files, err := filepath.Glob("testdata/*.txtar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, fullpath := range files {
+		t.Run(fullpath, func(t *testing.T) {
+			a, err := txtar.ParseFile(fullpath)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			r := &cue.Runtime{}
+			var in *cue.Instance
+			var out, errout []byte
+			outIndex := -1
+			errIndex := -1
+
+			for i, f := range a.Files {
+				switch path.Ext(f.Name) {
+				case ".avsc":
+					in, err = json.Decode(r, f.Name, f.Data)
+				case ".cue":
+					out = f.Data
+					outIndex = i
+				case ".err":
+					errout = f.Data
+					errIndex = i
+				}
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			updated := false
+
+			f, err := Extract(in, &Config{})
+			if err != nil {
+				got := bytes.TrimSpace([]byte(errors.Details(err, nil)))
+				errout = bytes.TrimSpace(errout)
+
+				if !cmp.Equal(errout, got) {
+					if cuetest.UpdateGoldenFiles && errIndex >= 0 {
+						a.Files[errIndex].Data = append(got, '\n')
+						updated = true
+					} else {
+						t.Error(cmp.Diff(string(errout), string(got)))
+					}
+				}
+			}
+
+			if f != nil {
+				b, err := format.Node(f, format.Simplify())
+				if err != nil {
+					t.Fatal(errors.Details(err, nil))
+				}
+
+				// Verify the generated CUE.
+				if _, err = r.Compile(fullpath, b); err != nil {
+					t.Fatal(errors.Details(err, nil))
+				}
+
+				b = bytes.TrimSpace(b)
+				out = bytes.TrimSpace(out)
+
+				if !cmp.Equal(b, out) {
+					if cuetest.UpdateGoldenFiles && outIndex >= 0 {
+						a.Files[outIndex].Data = append(b, '\n')
+						updated = true
+					} else {
+						t.Error(cmp.Diff(string(out), string(b)))
+					}
+				}
+			}
+
+			if updated {
+				err = ioutil.WriteFile(fullpath, txtar.Format(a), 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -44,9 +44,9 @@ func TestPackages(t *testing.T) {
 		value: &pkg1.OtherStruct{A: "car"},
 		want: `
 2 errors in empty disjunction:
-conflicting values null and {A:strings.ContainsAny("X"),P:"cuelang.org/go/encoding/gocode/testdata/pkg2".PickMe} (mismatched types null and struct):
+disjunct 1: conflicting values null and {A:strings.ContainsAny("X"),P:"cuelang.org/go/encoding/gocode/testdata/pkg2".PickMe} (mismatched types null and struct):
     pkg1/instance.cue:x:x
-A: invalid value "car" (does not satisfy strings.ContainsAny("X")):
+A: disjunct 2: invalid value "car" (does not satisfy strings.ContainsAny("X")):
     pkg1/instance.cue:x:x
     pkg1/instance.cue:x:x
 `,
@@ -55,9 +55,9 @@ A: invalid value "car" (does not satisfy strings.ContainsAny("X")):
 		value: &pkg1.MyStruct{A: 11, B: "dog"},
 		want: `
 2 errors in empty disjunction:
-conflicting values null and {A:<=10,B:(=~"cat"|*"dog"),O?:OtherStruct,I:"cuelang.org/go/encoding/gocode/testdata/pkg2".ImportMe} (mismatched types null and struct):
+disjunct 1: conflicting values null and {A:<=10,B:(=~"cat"|*"dog"),O?:OtherStruct,I:"cuelang.org/go/encoding/gocode/testdata/pkg2".ImportMe} (mismatched types null and struct):
     pkg1/instance.cue:x:x
-A: invalid value 11 (out of bound <=10):
+A: disjunct 2: invalid value 11 (out of bound <=10):
     pkg1/instance.cue:x:x
 `,
 	}, {
@@ -68,10 +68,10 @@ A: invalid value 11 (out of bound <=10):
 conflicting values null and {A:<=10,B:(=~"cat"|*"dog"),O?:OtherStruct,I:"cuelang.org/go/encoding/gocode/testdata/pkg2".ImportMe} (mismatched types null and struct):
     pkg1/instance.cue:x:x
 O: 2 errors in empty disjunction:
-O: conflicting values null and {A:strings.ContainsAny("X"),P:"cuelang.org/go/encoding/gocode/testdata/pkg2".PickMe} (mismatched types null and struct):
+O: disjunct 1: conflicting values null and {A:strings.ContainsAny("X"),P:"cuelang.org/go/encoding/gocode/testdata/pkg2".PickMe} (mismatched types null and struct):
     pkg1/instance.cue:x:x
     pkg1/instance.cue:x:x
-O.A: invalid value "car" (does not satisfy strings.ContainsAny("X")):
+O.A: disjunct 2: invalid value "car" (does not satisfy strings.ContainsAny("X")):
     pkg1/instance.cue:x:x
     pkg1/instance.cue:x:x
 `,
@@ -83,10 +83,10 @@ O.A: invalid value "car" (does not satisfy strings.ContainsAny("X")):
 conflicting values null and {A:<=10,B:(=~"cat"|*"dog"),O?:OtherStruct,I:"cuelang.org/go/encoding/gocode/testdata/pkg2".ImportMe} (mismatched types null and struct):
     pkg1/instance.cue:x:x
 O: 2 errors in empty disjunction:
-O: conflicting values null and {A:strings.ContainsAny("X"),P:"cuelang.org/go/encoding/gocode/testdata/pkg2".PickMe} (mismatched types null and struct):
+O: disjunct 1: conflicting values null and {A:strings.ContainsAny("X"),P:"cuelang.org/go/encoding/gocode/testdata/pkg2".PickMe} (mismatched types null and struct):
     pkg1/instance.cue:x:x
     pkg1/instance.cue:x:x
-O.P: invalid value 4 (out of bound >5):
+O.P: disjunct 2: invalid value 4 (out of bound >5):
     pkg2/instance.cue:x:x
 `,
 	}, {
@@ -28,15 +28,28 @@ import (
 	"cuelang.org/go/internal/value"
 )
 
-// Config has no options yet, but is defined for future extensibility.
+// Config configures a Codec.
 type Config struct {
+	// Validator, if non-nil, is called by Encode with the Go value after it
+	// has been successfully populated from CUE. This allows integrating
+	// additional validation of Go values, for instance based on struct tags,
+	// with the conversion from CUE. An error returned by Validator is
+	// returned by Encode.
+	Validator func(x interface{}) error
+
+	// BytesAsBase64 specifies that Encode converts CUE bytes values to Go
+	// strings using standard base64 encoding. By default, converting a bytes
+	// value to a Go string is an error.
+	BytesAsBase64 bool
 }
 
 // A Codec decodes and encodes CUE from and to Go values and validates and
 // completes Go values based on CUE templates.
 type Codec struct {
-	runtime *cue.Context
-	mutex   sync.RWMutex
+	runtime   *cue.Context
+	validator func(x interface{}) error
+	decodeOpt []cue.Option
+	mutex     sync.RWMutex
 }
 
 // New creates a new Codec for the given instance.
@@ -45,7 +58,14 @@ type Codec struct {
 // Runtime is not used elsewhere while using Codec. However, only the concurrent
 // use of Decode, Validate, and Complete is efficient.
 func New(r *cue.Runtime, c *Config) *Codec {
-	return &Codec{runtime: value.ConvertToContext(r)}
+	codec := &Codec{runtime: value.ConvertToContext(r)}
+	if c != nil {
+		codec.validator = c.Validator
+		if c.BytesAsBase64 {
+			codec.decodeOpt = append(codec.decodeOpt, cue.BytesAsBase64(true))
+		}
+	}
+	return codec
 }
 
 // ExtractType extracts a CUE value from a Go type.
@@ -86,11 +106,20 @@ func (c *Codec) Decode(x interface{}) (cue.Value, error) {
 }
 
 // Encode converts v to a Go value.
+//
+// If c was configured with a Validator, it is called with x after a
+// successful conversion.
 func (c *Codec) Encode(v cue.Value, x interface{}) error {
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
 
-	return v.Decode(x)
+	if err := v.Decode(x, c.decodeOpt...); err != nil {
+		return err
+	}
+	if c.validator != nil {
+		return c.validator(x)
+	}
+	return nil
 }
 
 var defaultCodec = New(value.ConvertToRuntime(cuecontext.New()), nil)
@@ -15,6 +15,7 @@
 package gocodec
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
@@ -226,6 +227,7 @@ func TestComplete(t *testing.T) {
 }
 
 func TestEncode(t *testing.T) {
+	type mode string
 	testCases := []struct {
 		in   string
 		dst  interface{}
@@ -234,6 +236,10 @@ func TestEncode(t *testing.T) {
 		in:   "4",
 		dst:  new(int),
 		want: 4,
+	}, {
+		in:   `*"fast" | "slow"`,
+		dst:  new(mode),
+		want: mode("fast"),
 	}}
 	r := &cue.Runtime{}
 	c := New(r, nil)
@@ -258,6 +264,84 @@ func TestEncode(t *testing.T) {
 	}
 }
 
+func TestEncodeValidator(t *testing.T) {
+	type S struct {
+		A int `json:"a"`
+	}
+	errTooLarge := errors.New("a too large")
+	var validated []interface{}
+
+	r := &cue.Runtime{}
+	c := New(r, &Config{
+		Validator: func(x interface{}) error {
+			validated = append(validated, x)
+			if x.(*S).A > 10 {
+				return errTooLarge
+			}
+			return nil
+		},
+	})
+
+	inst, err := r.Compile("test", "a: 4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &S{}
+	if err := c.Encode(inst.Value(), s); err != nil {
+		t.Fatal(err)
+	}
+	if len(validated) != 1 || validated[0] != s {
+		t.Errorf("validator not called with decoded value: %v", validated)
+	}
+
+	inst, err = r.Compile("test", "a: 20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Encode(inst.Value(), &S{}); err != errTooLarge {
+		t.Errorf("got error %v; want %v", err, errTooLarge)
+	}
+
+	// The validator is not called if decoding fails.
+	inst, err = r.Compile("test", `a: "foo"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	validated = nil
+	if err := c.Encode(inst.Value(), &S{}); err == nil {
+		t.Error("expected decoding error")
+	}
+	if len(validated) != 0 {
+		t.Errorf("validator called after failed decode")
+	}
+}
+
+func TestEncodeBytes(t *testing.T) {
+	type S struct {
+		Raw  []byte `json:"raw"`
+		Text []byte `json:"text"`
+		Enc  string `json:"enc"`
+	}
+	r := &cue.Runtime{}
+	inst, err := r.Compile("test", `{raw: 'a\x00', text: "hello", enc: 'a\x00'}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := New(r, nil).Encode(inst.Value(), &S{}); err == nil {
+		t.Error("expected error decoding bytes into string")
+	}
+
+	var s S
+	if err := New(r, &Config{BytesAsBase64: true}).Encode(inst.Value(), &s); err != nil {
+		t.Fatal(err)
+	}
+	want := S{Raw: []byte("a\x00"), Text: []byte("hello"), Enc: "YQA="}
+	if diff := cmp.Diff(s, want); diff != "" {
+		t.Error(diff)
+	}
+}
+
 func TestDecode(t *testing.T) {
 	testCases := []struct {
 		in   interface{}
@@ -296,6 +380,17 @@ func TestDecode(t *testing.T) {
 	t: {
 		B: 0
 	}
+}`,
+	}, {
+		in: func() interface{} {
+			type S struct {
+				Path string `cue:",path"`
+				A    string
+			}
+			return S{Path: "a.b"}
+		}(),
+		want: `{
+	A: ""
 }`,
 	}}
 	c := New(&cue.Runtime{}, nil)
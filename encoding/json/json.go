@@ -16,6 +16,7 @@
 package json
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -55,10 +56,40 @@ func Validate(b []byte, v cue.Value) error {
 	return nil
 }
 
+// An Option configures how JSON is extracted.
+type Option func(*options)
+
+type options struct {
+	lenient  bool
+	comments bool
+}
+
+// Lenient allows JSON with comments and trailing commas, as is common for
+// configuration files, also known as JSONC. Both line comments (//) and
+// block comments (/* */) are accepted. Extract is strict by default.
+func Lenient() Option {
+	return func(o *options) { o.lenient = true }
+}
+
+// PreserveComments retains the comments of lenient JSON in the resulting
+// CUE expression. Comments are attached to the neighboring fields and list
+// elements. As CUE only has line comments, block comments are converted to
+// line comments. It implies Lenient.
+func PreserveComments() Option {
+	return func(o *options) {
+		o.lenient = true
+		o.comments = true
+	}
+}
+
 // Extract parses JSON-encoded data to a CUE expression, using path for
 // position information.
-func Extract(path string, data []byte) (ast.Expr, error) {
-	expr, err := extract(path, data)
+func Extract(path string, data []byte, opts ...Option) (ast.Expr, error) {
+	var o options
+	for _, f := range opts {
+		f(&o)
+	}
+	expr, err := extract(path, data, o)
 	if err != nil {
 		return nil, err
 	}
@@ -71,14 +102,18 @@ func Extract(path string, data []byte) (ast.Expr, error) {
 //
 // Deprecated: use Extract and build using cue.Context.BuildExpr.
 func Decode(r *cue.Runtime, path string, data []byte) (*cue.Instance, error) {
-	expr, err := extract(path, data)
+	expr, err := extract(path, data, options{})
 	if err != nil {
 		return nil, err
 	}
 	return r.CompileExpr(expr)
 }
 
-func extract(path string, b []byte) (ast.Expr, error) {
+func extract(path string, b []byte, o options) (ast.Expr, error) {
+	var comments []comment
+	if o.lenient {
+		b, comments = stripJSONC(b)
+	}
 	expr, err := parser.ParseExpr(path, b)
 	if err != nil || !json.Valid(b) {
 		p := token.NoPos
@@ -89,9 +124,184 @@ func extract(path string, b []byte) (ast.Expr, error) {
 		err := json.Unmarshal(b, &x)
 		return nil, errors.Wrapf(err, p, "invalid JSON for file %q", path)
 	}
+	if o.comments {
+		addComments(expr, b, comments)
+	}
 	return expr, nil
 }
 
+// A comment is the byte range [start, end) of a comment in JSONC data.
+type comment struct {
+	start, end int
+	text       string // without the comment markers
+	block      bool
+}
+
+// stripJSONC removes comments and trailing commas from the JSONC data b.
+//
+// The returned data is b with comments and trailing commas replaced by
+// spaces, which retains the offsets and line numbers of all other tokens, so
+// that positions in the returned data are positions in b. The removed
+// comments are returned in order of appearance.
+func stripJSONC(b []byte) (data []byte, comments []comment) {
+	data = append([]byte(nil), b...)
+	blank := func(start, end int) {
+		for i := start; i < end; i++ {
+			if data[i] != '\n' {
+				data[i] = ' '
+			}
+		}
+	}
+
+	comma := -1 // offset of a comma that may be trailing
+	for i := 0; i < len(b); i++ {
+		switch c := b[i]; {
+		case c == '"':
+			for i++; i < len(b) && b[i] != '"'; i++ {
+				if b[i] == '\\' {
+					i++
+				}
+			}
+			comma = -1
+
+		case c == '/' && i+1 < len(b) && b[i+1] == '/':
+			end := bytes.IndexByte(b[i:], '\n')
+			if end < 0 {
+				end = len(b) - i
+			}
+			end += i
+			blank(i, end)
+			comments = append(comments, comment{i, end, string(b[i+2 : end]), false})
+			i = end - 1
+
+		case c == '/' && i+1 < len(b) && b[i+1] == '*':
+			end := bytes.Index(b[i+2:], []byte("*/"))
+			if end < 0 {
+				// Leave it to the JSON validation to fail.
+				return data, comments
+			}
+			end += i + 4
+			blank(i, end)
+			comments = append(comments, comment{i, end, string(b[i+2 : end-2]), true})
+			i = end - 1
+
+		case c == ',':
+			comma = i
+
+		case c == '}' || c == ']':
+			if comma >= 0 {
+				blank(comma, comma+1)
+			}
+			comma = -1
+
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+
+		default:
+			comma = -1
+		}
+	}
+	return data, comments
+}
+
+// addComments attaches the comments removed from data to the nodes of expr,
+// which is the result of parsing data. A comment is attached to the preceding
+// field or list element as a line comment if it is on the same line, and as a
+// doc comment to the following field or element otherwise. Comments after the
+// last element of a struct or list are attached to that element.
+func addComments(expr ast.Expr, data []byte, comments []comment) {
+	if len(comments) == 0 {
+		return
+	}
+	file := expr.Pos().File()
+
+	// Collect the elements of the innermost struct or list enclosing each
+	// offset. The top-level expression is the sole element of the file.
+	type scope struct {
+		node       ast.Node
+		start, end int
+		elems      []ast.Node
+	}
+	scopes := []scope{{expr, 0, len(data), []ast.Node{expr}}}
+	ast.Walk(expr, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.StructLit:
+			s := scope{node: x, start: x.Lbrace.Offset(), end: x.Rbrace.Offset()}
+			for _, e := range x.Elts {
+				s.elems = append(s.elems, e)
+			}
+			scopes = append(scopes, s)
+		case *ast.ListLit:
+			s := scope{node: x, start: x.Lbrack.Offset(), end: x.Rbrack.Offset()}
+			for _, e := range x.Elts {
+				s.elems = append(s.elems, e)
+			}
+			scopes = append(scopes, s)
+		}
+		return true
+	}, nil)
+
+	for _, c := range comments {
+		// Scopes are collected in pre-order, so the last one enclosing the
+		// comment is the innermost.
+		var sc scope
+		for _, s := range scopes {
+			if s.start <= c.start && c.end <= s.end {
+				sc = s
+			}
+		}
+
+		var before, after ast.Node
+		for _, e := range sc.elems {
+			if e.End().Offset() <= c.start {
+				before = e
+			} else if after == nil && e.Pos().Offset() >= c.end {
+				after = e
+			}
+		}
+		lineStart := bytes.LastIndexByte(data[:c.start], '\n') + 1
+		trailing := len(bytes.TrimSpace(data[lineStart:c.start])) > 0
+
+		var lines []string
+		if !c.block {
+			lines = append(lines, "//"+strings.TrimRight(c.text, " \t\r"))
+		} else {
+			for i, line := range strings.Split(c.text, "\n") {
+				line = strings.TrimSpace(line)
+				// Strip decorations of the form " * text".
+				if i > 0 {
+					line = strings.TrimSpace(strings.TrimPrefix(line, "*"))
+				}
+				if line != "" {
+					lines = append(lines, "// "+line)
+				}
+			}
+		}
+		if lines == nil {
+			continue
+		}
+		cg := &ast.CommentGroup{}
+		for _, line := range lines {
+			cg.List = append(cg.List, &ast.Comment{
+				Slash: file.Pos(c.start, token.NoRelPos),
+				Text:  line,
+			})
+		}
+
+		if after != nil && (before == nil || !trailing) {
+			cg.Doc = true
+			ast.AddComment(after, cg)
+			continue
+		}
+		if before == nil {
+			before = sc.node // an empty struct or list
+		}
+		cg.Doc = !trailing
+		cg.Line = trailing
+		cg.Position = 127
+		ast.AddComment(before, cg)
+	}
+}
+
 // NewDecoder configures a JSON decoder. The path is used to associate position
 // information with each node. The runtime may be nil if the decoder
 // is only used to extract to CUE ast objects.
@@ -138,6 +138,114 @@ func TestExtract(t *testing.T) {
 	}
 }
 
+func TestExtractLenient(t *testing.T) {
+	const jsonc = `{
+	// Server settings.
+	"port": 8080, /* default */
+	"hosts": [
+		"a",
+		"b", // trailing comma
+	],
+}`
+	testCases := []struct {
+		name string
+		in   string
+		opts []Option
+		out  string
+	}{{
+		name: "strict by default",
+		in:   jsonc,
+		out:  "invalid JSON for file \"strict by default\": invalid character '/' looking for beginning of object key string",
+	}, {
+		name: "lenient",
+		in:   jsonc,
+		opts: []Option{Lenient()},
+		out: `{
+	port: 8080
+	hosts: [
+		"a",
+		"b",
+	]
+}`,
+	}, {
+		name: "preserve comments",
+		in:   jsonc,
+		opts: []Option{PreserveComments()},
+		out: `{
+	// Server settings.
+	port: 8080 // default
+	hosts: [
+		"a",
+		"b", // trailing comma
+	]
+}`,
+	}, {
+		name: "multiline block comment",
+		in: `{
+	/*
+	 * The answer.
+	 * Do not change.
+	 */
+	"a": 42
+}`,
+		opts: []Option{PreserveComments()},
+		out: `{
+	// The answer.
+	// Do not change.
+	a: 42
+}`,
+	}, {
+		name: "comments after last element",
+		in: `// Top.
+{
+	"a": [
+		1,
+		// After one.
+	],
+	"b": {}, /* empty */
+	"c": { /* inside */ }
+	// After c.
+}
+// End.`,
+		opts: []Option{PreserveComments()},
+		out: `// Top.
+{
+	a: [
+		1,
+		// After one.
+	]
+	b: {} // empty
+	c: {} // inside
+	// After c.
+}
+// End.`,
+	}, {
+		name: "comment markers in strings",
+		in:   `{"url": "http://x/*y*/", "a": [1,],}`,
+		opts: []Option{Lenient()},
+		out:  `{url: "http://x/*y*/", a: [1]}`,
+	}, {
+		name: "non-JSON remains invalid",
+		in: `{a: 1, // comment
+}`,
+		opts: []Option{Lenient()},
+		out:  "invalid JSON for file \"non-JSON remains invalid\": invalid character 'a' looking for beginning of object key string",
+	}, {
+		name: "duplicate commas",
+		in:   `[1,,]`,
+		opts: []Option{Lenient()},
+		out:  "invalid JSON for file \"duplicate commas\": invalid character ']' looking for beginning of value",
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			e, err := Extract(tc.name, []byte(tc.in), tc.opts...)
+			toString(out, e, err)
+			assert.Equal(t, tc.out, out.String())
+		})
+	}
+}
+
 func toString(w *bytes.Buffer, e ast.Expr, err error) {
 	if err != nil {
 		fmt.Fprint(w, err)
@@ -150,3 +258,27 @@ func toString(w *bytes.Buffer, e ast.Expr, err error) {
 	}
 	fmt.Fprint(w, string(b))
 }
+
+func TestExtractCommentPositions(t *testing.T) {
+	const jsonc = `{
+	/* A block
+	   comment. */
+	"a": 1, // line
+	"b": 2
+}`
+	e, err := Extract("test", []byte(jsonc), PreserveComments())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := e.(*ast.StructLit)
+	for i, want := range []int{4, 5} {
+		if got := s.Elts[i].Pos().Line(); got != want {
+			t.Errorf("field %d: got line %d; want %d", i, got, want)
+		}
+	}
+	var lines []int
+	for _, cg := range ast.Comments(s.Elts[0]) {
+		lines = append(lines, cg.Pos().Line())
+	}
+	assert.Equal(t, []int{2, 4}, lines)
+}
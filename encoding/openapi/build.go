@@ -43,6 +43,7 @@ type buildContext struct {
 	exclusiveBool bool
 	nameFunc      func(inst cue.Value, path cue.Path) string
 	descFunc      func(v cue.Value) string
+	preferDocAttr bool
 	fieldFilter   *regexp.Regexp
 	evalDepth     int // detect cycles when resolving references
 
@@ -101,16 +102,17 @@ func schemas(g *Generator, inst cue.InstanceOrValue) (schemas *ast.StructLit, er
 	}
 
 	c := &buildContext{
-		inst:         val,
-		instExt:      val,
-		refPrefix:    "components/schemas",
-		expandRefs:   g.ExpandReferences,
-		structural:   g.ExpandReferences,
-		nameFunc:     g.NameFunc,
-		descFunc:     g.DescriptionFunc,
-		schemas:      &OrderedMap{},
-		externalRefs: map[string]*externalType{},
-		fieldFilter:  fieldFilter,
+		inst:          val,
+		instExt:       val,
+		refPrefix:     "components/schemas",
+		expandRefs:    g.ExpandReferences,
+		structural:    g.ExpandReferences,
+		nameFunc:      g.NameFunc,
+		descFunc:      g.DescriptionFunc,
+		preferDocAttr: g.PreferDocAttr,
+		schemas:       &OrderedMap{},
+		externalRefs:  map[string]*externalType{},
+		fieldFilter:   fieldFilter,
 	}
 	if g.ReferenceFunc != nil {
 		if !isInstance {
@@ -277,6 +279,9 @@ func (b *builder) getDoc(v cue.Value) {
 		for _, d := range v.Doc() {
 			doc = append(doc, d.Text())
 		}
+		if attr := docAttr(v); attr != "" && (len(doc) == 0 || b.ctx.preferDocAttr) {
+			doc = []string{attr}
+		}
 	}
 	if len(doc) > 0 {
 		str := strings.TrimSpace(strings.Join(doc, "\n\n"))
@@ -284,6 +289,16 @@ func (b *builder) getDoc(v cue.Value) {
 	}
 }
 
+// docAttr returns the description specified by a @doc attribute of v, if any.
+func docAttr(v cue.Value) string {
+	a := v.Attribute("doc")
+	if a.Err() != nil {
+		return ""
+	}
+	s, _ := a.String(0)
+	return s
+}
+
 func (b *builder) fillSchema(v cue.Value) *ast.StructLit {
 	if b.filled != nil {
 		return b.filled
@@ -62,6 +62,15 @@ type Config struct {
 	// the empty string is returned.
 	DescriptionFunc func(v cue.Value) string
 
+	// PreferDocAttr specifies that the @doc attribute of a field takes
+	// precedence over its doc comments for the description. By default,
+	// the description is taken from the doc comments of a field, if present,
+	// and from the first argument of a @doc attribute otherwise. Doc comments
+	// consisting of multiple comment groups or paragraphs are separated by
+	// blank lines, and their contents, such as Markdown, are included as is.
+	// This option has no effect if DescriptionFunc is set.
+	PreferDocAttr bool
+
 	// SelfContained causes all non-expanded external references to be included
 	// in this document.
 	SelfContained bool
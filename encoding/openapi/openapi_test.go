@@ -174,6 +174,14 @@ func TestParseDefinitions(t *testing.T) {
 				return "Randomly picked description from a set of size one."
 			},
 		},
+	}, {
+		in:     "doc.cue",
+		out:    "doc.json",
+		config: &openapi.Config{Info: info},
+	}, {
+		in:     "doc.cue",
+		out:    "doc-attr.json",
+		config: &openapi.Config{Info: info, PreferDocAttr: true},
 	}, {
 		in:  "refs.cue",
 		out: "refs.json",
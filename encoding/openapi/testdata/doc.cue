@@ -0,0 +1,20 @@
+// Config is the configuration of a server.
+//
+// It supports **Markdown**:
+//
+//   - item one
+//   - item two
+#Config: {
+	// Name of the server.
+	name: string @doc("The `name` of the server.")
+
+	// Port to listen on.
+	//
+	// Defaults to 8080.
+	port: int @doc("Listening port.")
+
+	// Host to bind to.
+	host: string
+
+	timeout: int @doc("Timeout in seconds.")
+}
@@ -79,16 +79,51 @@ func Decode(r *cue.Runtime, filename string, src interface{}) (*cue.Instance, er
 	return r.CompileFile(file)
 }
 
+// An EncodeOption configures Encode and EncodeStream.
+type EncodeOption func(o *encodeOptions)
+
+type encodeOptions struct {
+	anchorSize int
+}
+
+func (o *encodeOptions) yamlOptions() []cueyaml.Option {
+	if o.anchorSize <= 0 {
+		return nil
+	}
+	return []cueyaml.Option{cueyaml.Anchors(o.anchorSize)}
+}
+
+// Anchors deduplicates repeated subtrees using YAML anchors and aliases.
+//
+// Structurally identical mappings and sequences that consist of at least
+// minSize YAML nodes are emitted once, marked with an anchor, with subsequent
+// occurrences replaced by an alias to that anchor. Anchor names are derived
+// from the field name under which a subtree first occurs, so that they are
+// stable across runs. Anchors are scoped to a single YAML document.
+//
+// Not all YAML consumers support aliases, so this is disabled by default.
+func Anchors(minSize int) EncodeOption {
+	return func(o *encodeOptions) { o.anchorSize = minSize }
+}
+
 // Encode returns the YAML encoding of v.
-func Encode(v cue.Value) ([]byte, error) {
+func Encode(v cue.Value, opts ...EncodeOption) ([]byte, error) {
+	o := &encodeOptions{}
+	for _, f := range opts {
+		f(o)
+	}
 	n := v.Syntax(cue.Final())
-	b, err := cueyaml.Encode(n)
+	b, err := cueyaml.Encode(n, o.yamlOptions()...)
 	return b, err
 }
 
 // EncodeStream returns the YAML encoding of iter, where consecutive values
 // of iter are separated with a `---`.
-func EncodeStream(iter cue.Iterator) ([]byte, error) {
+func EncodeStream(iter cue.Iterator, opts ...EncodeOption) ([]byte, error) {
+	o := &encodeOptions{}
+	for _, f := range opts {
+		f(o)
+	}
 	// TODO: return an io.Reader and allow asynchronous processing.
 	buf := &bytes.Buffer{}
 	for i := 0; iter.Next(); i++ {
@@ -96,7 +131,7 @@ func EncodeStream(iter cue.Iterator) ([]byte, error) {
 			buf.WriteString("---\n")
 		}
 		n := iter.Value().Syntax(cue.Final())
-		b, err := cueyaml.Encode(n)
+		b, err := cueyaml.Encode(n, o.yamlOptions()...)
 		if err != nil {
 			return nil, err
 		}
@@ -245,3 +245,33 @@ func TestYAMLValues(t *testing.T) {
 		})
 	}
 }
+
+func TestEncodeAnchors(t *testing.T) {
+	ctx := cuecontext.New()
+	v := ctx.CompileString(`[{
+		a: {x: 1, y: 2}
+		b: {x: 1, y: 2}
+	}, {
+		c: {x: 1, y: 2}
+	}]`)
+	list, err := v.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := EncodeStream(list, Anchors(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `a: &a
+  x: 1
+  "y": 2
+b: *a
+---
+c:
+  x: 1
+  "y": 2
+`
+	if got := string(b); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -287,6 +287,15 @@ func (c *OpContext) yield(
 	}
 	y := comp.Clauses[0]
 
+	if c.tracer != nil {
+		v := node
+		if v == nil {
+			v = c.vertex
+		}
+		c.tracer.Enter(TraceComprehension, v, comp)
+		defer c.tracer.Leave(TraceComprehension, v, comp)
+	}
+
 	saved := c.PushState(env, y.Source())
 	if node != nil {
 		defer c.PopArc(c.PushArc(node))
@@ -302,6 +311,10 @@ func (c *OpContext) yield(
 func (s *compState) yield(env *Environment) (ok bool) {
 	c := s.ctx
 	if s.i >= len(s.comp.Clauses) {
+		if err := c.checkLimits(nil); err != nil {
+			c.AddBottom(err)
+			return false
+		}
 		s.f(env)
 		return true
 	}
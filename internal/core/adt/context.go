@@ -15,6 +15,7 @@
 package adt
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -217,6 +218,8 @@ type OpContext struct {
 
 	stats        stats.Counts
 	freeListNode *nodeContext
+	profile      *Profile
+	tracer       Tracer
 
 	e         *Environment
 	ci        CloseInfo
@@ -264,6 +267,12 @@ type OpContext struct {
 	// as an error if this is true.
 	// TODO: strictly separate validators and functions.
 	IsValidator bool
+
+	// limits, if not nil, bounds the resources used for evaluation. See
+	// SetLimits.
+	limits   *Limits
+	limitErr *Bottom
+	done     context.Context
 }
 
 func (c *OpContext) CloseInfo() CloseInfo { return c.ci }
@@ -125,6 +125,17 @@ func (n *nodeContext) expandDisjuncts(
 	recursive, last bool) {
 
 	n.ctx.stats.Disjuncts++
+	if p := n.ctx.profile; p != nil {
+		p.entry(n.node).Disjuncts++
+	}
+	if t := n.ctx.tracer; t != nil {
+		t.Enter(TraceDisjunct, n.node, nil)
+		defer t.Leave(TraceDisjunct, n.node, nil)
+	}
+
+	if err := n.ctx.checkLimits(nil); err != nil {
+		n.addBottom(err)
+	}
 
 	// refNode is used to collect cyclicReferences for all disjuncts to be
 	// passed up to the parent node. Note that because the node in the parent
@@ -220,6 +231,11 @@ func (n *nodeContext) expandDisjuncts(
 				n.ctx.inDisjunct++
 			}
 
+			// ends records the number of errors added after processing each
+			// disjunct, so that errors can be attributed to disjuncts.
+			start := len(n.disjunctErrs)
+			var ends []int
+
 			for _, dn := range a {
 				switch {
 				case d.expr != nil:
@@ -234,6 +250,7 @@ func (n *nodeContext) expandDisjuncts(
 						newMode := mode(d.hasDefaults, v.Default)
 
 						cn.expandDisjuncts(state, n, newMode, true, last)
+						ends = append(ends, len(n.disjunctErrs)-start)
 
 						// Record the cyclicReferences of the conjunct in the
 						// parent list.
@@ -258,6 +275,7 @@ func (n *nodeContext) expandDisjuncts(
 						newMode := mode(d.hasDefaults, i < d.value.NumDefaults)
 
 						cn.expandDisjuncts(state, n, newMode, true, last)
+						ends = append(ends, len(n.disjunctErrs)-start)
 
 						// See comment above.
 						for r := n.node.cyclicReferences; r != nil; r = r.Next {
@@ -274,6 +292,11 @@ func (n *nodeContext) expandDisjuncts(
 			}
 
 			if len(n.disjuncts) == 0 {
+				// Errors can only be attributed to a single disjunct if
+				// there is one disjunction.
+				if len(n.disjunctions) == 1 {
+					labelDisjunctErrors(n.disjunctErrs[start:], ends)
+				}
 				n.makeError()
 			}
 
@@ -544,6 +567,42 @@ func combineDefault(a, b defaultMode) defaultMode {
 	return b
 }
 
+// labelDisjunctErrors labels the errors in a with the disjunct that caused
+// them, so that users can tell which error belongs to which disjunct. The
+// errors of disjunct i end at index ends[i] of a.
+//
+// Errors are only labeled if there are at least two disjuncts, each of which
+// contributed a single error, and if all these errors are different. Errors
+// of nested disjunctions are not labeled.
+func labelDisjunctErrors(a []*Bottom, ends []int) {
+	if len(ends) < 2 || len(a) != len(ends) {
+		return
+	}
+	var errs errors.Error
+	for i, b := range a {
+		if ends[i] != i+1 {
+			return
+		}
+		if _, ok := b.Err.(*ValueError); !ok {
+			return
+		}
+		errs = errors.Append(errs, b.Err)
+	}
+	if len(errors.Errors(errors.Sanitize(errs))) != len(a) {
+		return
+	}
+
+	for i, b := range a {
+		x := *b
+		err := *b.Err.(*ValueError)
+		format, args := err.Msg()
+		args = append([]interface{}{i + 1}, args...)
+		err.Message = errors.NewMessage("disjunct %d: "+format, args)
+		x.Err = &err
+		a[i] = &x
+	}
+}
+
 // disjunctError returns a compound error for a failed disjunction.
 //
 // TODO(perf): the set of errors is now computed during evaluation. Eventually,
@@ -159,6 +159,15 @@ func (c *OpContext) Unify(v *Vertex, state VertexStatus) {
 		}()
 	}
 
+	if c.profile != nil {
+		c.profile.enter(v)
+		defer c.profile.exit()
+	}
+	if c.tracer != nil {
+		c.tracer.Enter(TraceUnify, v, nil)
+		defer c.tracer.Leave(TraceUnify, v, nil)
+	}
+
 	// Ensure a node will always have a nodeContext after calling Unify if it is
 	// not yet Finalized.
 	n := v.getNodeContext(c, 1)
@@ -211,6 +220,11 @@ func (c *OpContext) Unify(v *Vertex, state VertexStatus) {
 
 		c.stats.Unifications++
 
+		if err := c.checkLimits(v); err != nil {
+			v.SetValue(c, Finalized, err)
+			return
+		}
+
 		// Set the cache to a cycle error to ensure a cyclic reference will result
 		// in an error if applicable. A cyclic error may be ignored for
 		// non-expression references. The cycle error may also be removed as soon
@@ -1424,6 +1438,11 @@ func (n *nodeContext) addErr(err errors.Error) {
 // into the nodeContext if successful or queue it for later evaluation if it is
 // incomplete or is not value.
 func (n *nodeContext) addExprConjunct(v Conjunct, state VertexStatus) {
+	if t := n.ctx.tracer; t != nil {
+		t.Enter(TraceConjunct, n.node, v.Expr())
+		defer t.Leave(TraceConjunct, n.node, v.Expr())
+	}
+
 	env := v.Env
 	id := v.CloseInfo
 
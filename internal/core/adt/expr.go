@@ -1537,6 +1537,10 @@ type Builtin struct {
 	Result Kind
 	Func   func(c *OpContext, args []Value) Expr
 
+	// Variadic indicates that the last parameter may be passed any number
+	// of times, including zero.
+	Variadic bool
+
 	Package Feature
 	Name    string
 }
@@ -1570,7 +1574,7 @@ func (x *Builtin) Kind() Kind {
 }
 
 func (x *Builtin) BareValidator() *BuiltinValidator {
-	if len(x.Params) != 1 ||
+	if len(x.Params) != 1 || x.Variadic ||
 		(x.Result != BoolKind && x.Result != BottomKind) {
 		return nil
 	}
@@ -1580,7 +1584,8 @@ func (x *Builtin) BareValidator() *BuiltinValidator {
 // IsValidator reports whether b should be interpreted as a Validator for the
 // given number of arguments.
 func (b *Builtin) IsValidator(numArgs int) bool {
-	return numArgs == len(b.Params)-1 &&
+	return !b.Variadic &&
+		numArgs == len(b.Params)-1 &&
 		b.Result&^BoolKind == 0 &&
 		b.Params[numArgs].Default() == nil
 }
@@ -1595,30 +1600,40 @@ func bottom(v Value) *Bottom {
 
 func (x *Builtin) call(c *OpContext, p token.Pos, validate bool, args []Value) Expr {
 	fun := x // right now always x.
-	if len(args) > len(x.Params) {
-		c.addErrf(0, p,
-			"too many arguments in call to %s (have %d, want %d)",
-			fun, len(args), len(x.Params))
-		return nil
-	}
-	for i := len(args); i < len(x.Params); i++ {
-		v := x.Params[i].Default()
-		if v == nil {
+	if x.Variadic {
+		if n := len(x.Params) - 1; len(args) < n {
 			c.addErrf(0, p,
-				"not enough arguments in call to %s (have %d, want %d)",
+				"not enough arguments in call to %s (have %d, want at least %d)",
+				fun, len(args), n)
+			return nil
+		}
+	} else {
+		if len(args) > len(x.Params) {
+			c.addErrf(0, p,
+				"too many arguments in call to %s (have %d, want %d)",
 				fun, len(args), len(x.Params))
 			return nil
 		}
-		args = append(args, v)
+		for i := len(args); i < len(x.Params); i++ {
+			v := x.Params[i].Default()
+			if v == nil {
+				c.addErrf(0, p,
+					"not enough arguments in call to %s (have %d, want %d)",
+					fun, len(args), len(x.Params))
+				return nil
+			}
+			args = append(args, v)
+		}
 	}
 	for i, a := range args {
-		if x.Params[i].Kind() == BottomKind {
+		param := x.param(i)
+		if param.Kind() == BottomKind {
 			continue
 		}
 		if b := bottom(a); b != nil {
 			return b
 		}
-		if k := kind(a); x.Params[i].Kind()&k == BottomKind {
+		if k := kind(a); param.Kind()&k == BottomKind {
 			code := EvalError
 			b, _ := args[i].(*Bottom)
 			if b != nil {
@@ -1626,10 +1641,10 @@ func (x *Builtin) call(c *OpContext, p token.Pos, validate bool, args []Value) E
 			}
 			c.addErrf(code, pos(a),
 				"cannot use %s (type %s) as %s in argument %d to %s",
-				a, k, x.Params[i].Kind(), i+1, fun)
+				a, k, param.Kind(), i+1, fun)
 			return nil
 		}
-		v := x.Params[i].Value
+		v := param.Value
 		if _, ok := v.(*BasicType); !ok {
 			env := c.Env(0)
 			x := &BinaryExpr{Op: AndOp, X: v, Y: a}
@@ -1655,6 +1670,14 @@ func (x *Builtin) call(c *OpContext, p token.Pos, validate bool, args []Value) E
 	return ret
 }
 
+// param returns the parameter for the i-th argument of a call to x.
+func (x *Builtin) param(i int) Param {
+	if n := len(x.Params) - 1; x.Variadic && i > n {
+		return x.Params[n]
+	}
+	return x.Params[i]
+}
+
 func (x *Builtin) Source() ast.Node { return nil }
 
 // A BuiltinValidator is a Value that results from evaluation a partial call
@@ -1690,10 +1713,17 @@ func (x *BuiltinValidator) validate(c *OpContext, v Value) *Bottom {
 	args[0] = v
 	copy(args[1:], x.Args)
 
-	return validateWithBuiltin(c, x.Pos(), x.Builtin, args)
+	var exprs []Expr
+	if x.Src != nil {
+		exprs = x.Src.Args
+	}
+	return validateWithBuiltin(c, x.Pos(), x.Builtin, args, exprs)
 }
 
-func validateWithBuiltin(c *OpContext, src token.Pos, b *Builtin, args []Value) *Bottom {
+// validateWithBuiltin validates args[0] with builtin b using the remaining
+// arguments. If not nil, exprs holds the expressions from which these
+// remaining arguments were computed.
+func validateWithBuiltin(c *OpContext, src token.Pos, b *Builtin, args []Value, exprs []Expr) *Bottom {
 	var severeness ErrorCode
 	var err errors.Error
 
@@ -1727,6 +1757,14 @@ func validateWithBuiltin(c *OpContext, src token.Pos, b *Builtin, args []Value)
 			if i > 0 {
 				_, _ = buf.WriteString(", ")
 			}
+			// A struct argument, such as the key function of
+			// list.UniqueItemsBy, may have fields that are incomplete until
+			// it is filled in by the builtin. Show how it was specified
+			// rather than its partially evaluated value.
+			if a.Kind() == StructKind && i < len(exprs) {
+				buf.WriteString(c.Str(exprs[i]))
+				continue
+			}
 			buf.WriteString(c.Str(a))
 		}
 		buf.WriteString(")")
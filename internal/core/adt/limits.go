@@ -0,0 +1,120 @@
+// Copyright 2022 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adt
+
+import (
+	"context"
+	"time"
+
+	"cuelang.org/go/cue/errors"
+)
+
+// Limits bounds the resources used by an evaluation. A zero value for any of
+// the fields means the respective resource is not bounded.
+type Limits struct {
+	// MaxIterations bounds the number of unifications.
+	MaxIterations int
+
+	// MaxDepth bounds the nesting depth of evaluated vertices.
+	MaxDepth int
+
+	// MaxDisjuncts bounds the number of disjuncts processed.
+	MaxDisjuncts int
+
+	// Timeout bounds the wall-clock time of an evaluation.
+	Timeout time.Duration
+}
+
+// ErrLimitExceeded is matched by all errors that result from exceeding
+// Limits.
+var ErrLimitExceeded = errors.New("evaluation limit exceeded")
+
+// limitExceeded is the cause of all errors that result from exceeding Limits.
+// It matches ErrLimitExceeded and wraps the underlying cause, if any.
+type limitExceeded struct {
+	cause error
+}
+
+func (e limitExceeded) Error() string        { return ErrLimitExceeded.Error() }
+func (e limitExceeded) Is(target error) bool { return target == ErrLimitExceeded }
+func (e limitExceeded) Unwrap() error        { return e.cause }
+
+// SetLimits bounds the resources used by subsequent evaluations with c.
+// Once a limit is exceeded, any vertex that is subsequently evaluated with c
+// will be finalized with the same error, which is also reported by
+// LimitError. The returned function must be called to release resources once
+// evaluation is done.
+//
+// TODO: vertices shared with other evaluations, such as those of imported
+// packages, will retain such errors.
+func (c *OpContext) SetLimits(l Limits) (release func()) {
+	c.limits = &l
+	c.limitErr = nil
+	c.done = nil
+	release = func() {}
+	if l.Timeout > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), l.Timeout)
+		c.done = ctx
+		release = cancel
+	}
+	return release
+}
+
+// LimitError reports the error resulting from exceeding the limits set with
+// SetLimits or nil if no limit was exceeded.
+func (c *OpContext) LimitError() *Bottom {
+	return c.limitErr
+}
+
+// checkLimits reports an error if evaluating v exceeds the limits of c.
+func (c *OpContext) checkLimits(v *Vertex) *Bottom {
+	l := c.limits
+	if l == nil || c.limitErr != nil {
+		return c.limitErr
+	}
+
+	var err *ValueError
+	var cause error
+	switch {
+	case c.done != nil && c.done.Err() != nil:
+		err = c.Newf("timed out after %v", l.Timeout)
+		cause = c.done.Err()
+
+	case l.MaxIterations > 0 && c.stats.Unifications > l.MaxIterations:
+		err = c.Newf("too many iterations (limit %d)", l.MaxIterations)
+
+	case l.MaxDisjuncts > 0 && c.stats.Disjuncts > l.MaxDisjuncts:
+		err = c.Newf("too many disjuncts (limit %d)", l.MaxDisjuncts)
+
+	case l.MaxDepth > 0 && v != nil && vertexDepth(v) > l.MaxDepth:
+		err = c.Newf("value nested too deeply (limit %d)", l.MaxDepth)
+
+	default:
+		return nil
+	}
+
+	c.limitErr = &Bottom{
+		Err:  errors.Wrap(err, limitExceeded{cause}),
+		Code: EvalError,
+	}
+	return c.limitErr
+}
+
+func vertexDepth(v *Vertex) (depth int) {
+	for ; v.Parent != nil; v = v.Parent {
+		depth++
+	}
+	return depth
+}
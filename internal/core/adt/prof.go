@@ -16,6 +16,7 @@ package adt
 
 import (
 	"sync"
+	"time"
 
 	"cuelang.org/go/cue/stats"
 )
@@ -45,3 +46,71 @@ func TotalStats() stats.Counts {
 	countsMu.Unlock()
 	return s
 }
+
+// A Profile records the cost of evaluating individual vertices. It is
+// populated during evaluation with an OpContext for which SetProfile was
+// called.
+type Profile struct {
+	entries map[*Vertex]*ProfileEntry
+	stack   []profileFrame
+}
+
+// A ProfileEntry holds the costs recorded for a single vertex.
+type ProfileEntry struct {
+	// Time is the time spent unifying the vertex, excluding time spent
+	// unifying other vertices in the meantime.
+	Time time.Duration
+
+	// Calls is the number of calls to Unify for the vertex.
+	Calls int
+
+	// Disjuncts is the number of disjuncts processed for the vertex.
+	Disjuncts int
+}
+
+type profileFrame struct {
+	v     *Vertex
+	start time.Time
+	inner time.Duration // time spent in nested frames
+}
+
+// Entries returns the costs recorded for each evaluated vertex. Disjuncts of
+// a vertex are recorded separately from the vertex itself.
+func (p *Profile) Entries() map[*Vertex]*ProfileEntry {
+	return p.entries
+}
+
+func (p *Profile) entry(v *Vertex) *ProfileEntry {
+	e := p.entries[v]
+	if e == nil {
+		if p.entries == nil {
+			p.entries = map[*Vertex]*ProfileEntry{}
+		}
+		e = &ProfileEntry{}
+		p.entries[v] = e
+	}
+	return e
+}
+
+func (p *Profile) enter(v *Vertex) {
+	p.entry(v).Calls++
+	p.stack = append(p.stack, profileFrame{v: v, start: time.Now()})
+}
+
+func (p *Profile) exit() {
+	n := len(p.stack) - 1
+	f := p.stack[n]
+	p.stack = p.stack[:n]
+
+	d := time.Since(f.start)
+	p.entry(f.v).Time += d - f.inner
+	if n > 0 {
+		p.stack[n-1].inner += d
+	}
+}
+
+// SetProfile causes subsequent evaluations with c to record their costs in p.
+// Profiling is disabled if p is nil.
+func (c *OpContext) SetProfile(p *Profile) {
+	c.profile = p
+}
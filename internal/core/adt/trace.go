@@ -0,0 +1,62 @@
+// Copyright 2022 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adt
+
+// A TraceKind identifies a kind of evaluation step reported to a Tracer.
+type TraceKind int8
+
+const (
+	// TraceUnify is the unification of a vertex.
+	TraceUnify TraceKind = iota
+
+	// TraceConjunct is the addition of a conjunct to a vertex.
+	TraceConjunct
+
+	// TraceDisjunct is the expansion of the disjunctions of a vertex.
+	TraceDisjunct
+
+	// TraceComprehension is the evaluation of the clauses of a comprehension.
+	TraceComprehension
+)
+
+func (k TraceKind) String() string {
+	switch k {
+	case TraceUnify:
+		return "unify"
+	case TraceConjunct:
+		return "conjunct"
+	case TraceDisjunct:
+		return "disjunct"
+	case TraceComprehension:
+		return "comprehension"
+	}
+	return "unknown"
+}
+
+// A Tracer is notified of the steps taken by the evaluator.
+//
+// Enter is called when a step for vertex v starts and Leave when it ends.
+// Calls are properly nested. The node n, which may be nil, is the expression
+// being evaluated in this step. Upon Leave, v reflects the result of the step.
+type Tracer interface {
+	Enter(kind TraceKind, v *Vertex, n Node)
+	Leave(kind TraceKind, v *Vertex, n Node)
+}
+
+// SetTracer causes subsequent evaluations with c to report their steps to t.
+// Tracing is disabled if t is nil.
+func (c *OpContext) SetTracer(t Tracer) {
+	c.tracer = t
+}
@@ -147,6 +147,22 @@ func isOptional(f *reflect.StructField) bool {
 	return isOptional
 }
 
+// isPathField reports whether f is a string field with a cue:",path" tag.
+// Such a field is set to the path of the CUE value it is decoded from and is
+// not part of the CUE representation of a struct.
+func isPathField(f *reflect.StructField) bool {
+	tag, ok := f.Tag.Lookup("cue")
+	if !ok || f.Type.Kind() != reflect.String {
+		return false
+	}
+	for _, opt := range strings.Split(tag, ",")[1:] {
+		if opt == "path" {
+			return true
+		}
+	}
+	return false
+}
+
 // isOmitEmpty means that the zero value is interpreted as undefined.
 func isOmitEmpty(f *reflect.StructField) bool {
 	isOmitEmpty := false
@@ -418,7 +434,7 @@ func convertRec(ctx *adt.OpContext, nilIsTop bool, x interface{}) adt.Value {
 			t := value.Type()
 			for i := 0; i < value.NumField(); i++ {
 				sf := t.Field(i)
-				if sf.PkgPath != "" {
+				if sf.PkgPath != "" || isPathField(&sf) {
 					continue
 				}
 				val := value.Field(i)
@@ -664,7 +680,7 @@ func goTypeToValueRec(ctx *adt.OpContext, allowNullDefault bool, t reflect.Type)
 
 		for i := 0; i < t.NumField(); i++ {
 			f := t.Field(i)
-			if f.PkgPath != "" {
+			if f.PkgPath != "" || isPathField(&f) {
 				continue
 			}
 			_, ok := f.Tag.Lookup("cue")
@@ -31,6 +31,11 @@ type compactPrinter struct {
 }
 
 func (w *compactPrinter) node(n adt.Node) {
+	if !w.enter(n) {
+		return
+	}
+	defer w.leave(n)
+
 	switch x := n.(type) {
 	case *adt.Vertex:
 		if x.BaseValue == nil || (w.cfg.Raw && !x.IsData()) {
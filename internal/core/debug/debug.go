@@ -40,6 +40,20 @@ type Config struct {
 	Cwd     string
 	Compact bool
 	Raw     bool
+
+	// Closedness annotates struct and list vertices with their closedness
+	// state and the pattern constraints that determine which fields they
+	// allow. It has no effect in compact mode.
+	Closedness bool
+
+	// MaxDepth limits the nesting depth of printed nodes. Nodes nested more
+	// deeply are printed as "...". A value of 0 means no limit.
+	//
+	// Every node counts as a level, not just structs and lists. For instance,
+	// a struct literal nested within a field of another struct literal is at
+	// depth 3, as the field itself counts as well. This bounds the recursion
+	// for any kind of nesting, including deeply nested expressions.
+	MaxDepth int
 }
 
 // WriteNode writes a string representation of the node to w.
@@ -72,6 +86,9 @@ type printer struct {
 	indent string
 	cfg    *Config
 
+	depth    int
+	visiting []*adt.Vertex // vertices on the current recursion path
+
 	// modes:
 	// - show vertex
 	// - show original conjuncts
@@ -159,7 +176,81 @@ func (w *printer) interpolation(x *adt.Interpolation) {
 	w.string(quote)
 }
 
+// closedness writes the closedness state of x and the pattern constraints of
+// the structs that contributed to it as comments.
+func (w *printer) closedness(x *adt.Vertex) {
+	switch x.BaseValue.(type) {
+	case *adt.StructMarker, *adt.ListMarker:
+	default:
+		return
+	}
+
+	saved := w.indent
+	w.indent += "// "
+	defer func() { w.indent = saved }()
+
+	w.string("\n")
+	switch {
+	case x.Closed:
+		w.string("closed: recursive")
+	case x.IsClosedList(), x.IsClosedStruct():
+		w.string("closed: true")
+	default:
+		w.string("closed: false")
+	}
+
+	seen := map[*adt.StructLit]bool{}
+	for _, s := range x.Structs {
+		if seen[s.StructLit] {
+			continue
+		}
+		seen[s.StructLit] = true
+		for _, d := range s.Decls {
+			switch d.(type) {
+			case *adt.BulkOptionalField, *adt.Ellipsis:
+				w.string("\n")
+				w.string("pattern: ")
+				w.node(d)
+			}
+		}
+	}
+}
+
+// enter reports whether n should be printed and, if so, records that the
+// printer descends into n. A Vertex that is already being printed is printed
+// as "<cycle>" instead. Each call that returns true must be matched by a call
+// to leave.
+func (w *printer) enter(n adt.Node) bool {
+	if w.cfg.MaxDepth > 0 && w.depth >= w.cfg.MaxDepth {
+		w.string("...")
+		return false
+	}
+	if v, ok := n.(*adt.Vertex); ok {
+		for _, x := range w.visiting {
+			if x == v {
+				w.string("<cycle>")
+				return false
+			}
+		}
+		w.visiting = append(w.visiting, v)
+	}
+	w.depth++
+	return true
+}
+
+func (w *printer) leave(n adt.Node) {
+	w.depth--
+	if _, ok := n.(*adt.Vertex); ok {
+		w.visiting = w.visiting[:len(w.visiting)-1]
+	}
+}
+
 func (w *printer) node(n adt.Node) {
+	if !w.enter(n) {
+		return
+	}
+	defer w.leave(n)
+
 	switch x := n.(type) {
 	case *adt.Vertex:
 		var kind adt.Kind
@@ -182,6 +273,10 @@ func (w *printer) node(n adt.Node) {
 		w.indent += "  "
 		defer func() { w.indent = saved }()
 
+		if w.cfg.Closedness {
+			w.closedness(x)
+		}
+
 		switch v := x.BaseValue.(type) {
 		case nil:
 		case *adt.Bottom:
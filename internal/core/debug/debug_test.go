@@ -0,0 +1,181 @@
+// Copyright 2022 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug_test
+
+import (
+	"testing"
+
+	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/internal/core/adt"
+	"cuelang.org/go/internal/core/debug"
+	"cuelang.org/go/internal/core/runtime"
+	"cuelang.org/go/internal/value"
+)
+
+func TestCycles(t *testing.T) {
+	r := runtime.New()
+	a := adt.MakeStringLabel(r, "a")
+	b := adt.MakeStringLabel(r, "b")
+	c := adt.MakeStringLabel(r, "c")
+
+	// Construct a vertex with a field that refers back to the vertex itself.
+	root := &adt.Vertex{Label: c, BaseValue: &adt.StructMarker{}}
+	arc := &adt.Vertex{
+		Label:     a,
+		BaseValue: &adt.StructMarker{},
+		Arcs:      []*adt.Vertex{root},
+	}
+	root.Arcs = []*adt.Vertex{arc}
+
+	// Sibling fields that share the same vertex are not cycles.
+	shared := &adt.Vertex{Label: b, BaseValue: &adt.Top{}}
+	siblings := &adt.Vertex{
+		BaseValue: &adt.StructMarker{},
+		Arcs:      []*adt.Vertex{shared, shared},
+	}
+
+	testCases := []struct {
+		name    string
+		node    adt.Node
+		compact bool
+		want    string
+	}{{
+		name:    "cycle/compact",
+		node:    root,
+		compact: true,
+		want:    `{a:{c:<cycle>}}`,
+	}, {
+		name: "cycle",
+		node: root,
+		want: "(struct){\n  a: (struct){\n    c: <cycle>\n  }\n}",
+	}, {
+		name:    "shared/compact",
+		node:    siblings,
+		compact: true,
+		want:    `{b:_,b:_}`,
+	}, {
+		name: "shared",
+		node: siblings,
+		want: "(struct){\n  b: (_){ _ }\n  b: (_){ _ }\n}",
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := debug.NodeString(r, tc.node, &debug.Config{Compact: tc.compact})
+			if got != tc.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	r := runtime.New()
+	a := adt.MakeStringLabel(r, "a")
+
+	var x adt.Expr = &adt.Top{}
+	for i := 0; i < 10000; i++ {
+		x = &adt.StructLit{Decls: []adt.Decl{
+			&adt.Field{Label: a, Value: x},
+		}}
+	}
+
+	// Both the struct literals and the fields count towards the depth.
+	testCases := []struct {
+		name string
+		cfg  *debug.Config
+		want string
+	}{{
+		name: "compact",
+		cfg:  &debug.Config{Compact: true, MaxDepth: 5},
+		want: `{a:{a:{...}}}`,
+	}, {
+		name: "indented",
+		cfg:  &debug.Config{MaxDepth: 5},
+		want: "{\n  a: {\n    a: {\n      ...\n    }\n  }\n}",
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := debug.NodeString(r, x, tc.cfg)
+			if got != tc.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+			// Output must be stable across runs.
+			if again := debug.NodeString(r, x, tc.cfg); again != got {
+				t.Errorf("unstable output:\n%s\nand:\n%s", got, again)
+			}
+		})
+	}
+}
+
+func TestClosedness(t *testing.T) {
+	v := cuecontext.New().CompileString(`
+	#A: {a: int, [=~"^x"]: string}
+	b: #A
+	c: {d: 1}
+	`)
+	r, x := value.ToInternal(v)
+
+	testCases := []struct {
+		name string
+		cfg  *debug.Config
+		want string
+	}{{
+		name: "off",
+		cfg:  &debug.Config{},
+		want: `(struct){
+  #A: (#struct){
+    a: (int){ int }
+  }
+  b: (#struct){
+    a: (int){ int }
+  }
+  c: (struct){
+    d: (int){ 1 }
+  }
+}`,
+	}, {
+		name: "on",
+		cfg:  &debug.Config{Closedness: true},
+		want: `(struct){
+  // closed: false
+  #A: (#struct){
+    // closed: recursive
+    // pattern: [=~"^x"]: string
+    a: (int){ int }
+  }
+  b: (#struct){
+    // closed: true
+    // pattern: [=~"^x"]: string
+    a: (int){ int }
+  }
+  c: (struct){
+    // closed: false
+    d: (int){ 1 }
+  }
+}`,
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := debug.NodeString(r, x, tc.cfg)
+			if got != tc.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+	// The output without annotations must be the same as the default.
+	if got, want := debug.NodeString(r, x, &debug.Config{}), debug.NodeString(r, x, nil); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright 2022 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"cuelang.org/go/internal/core/adt"
+	"cuelang.org/go/internal/core/dep"
+)
+
+// WriteDot writes the graph of v to w in the DOT language of Graphviz.
+//
+// Each vertex is a node, with solid edges from a vertex to its arcs. Dashed
+// edges lead from a vertex to the vertices referenced by its conjuncts, such
+// as through field, selector, let and import references. Referenced vertices
+// that are not part of the graph of v, like those of imported packages, are
+// drawn as separate nodes labeled with their import path or path.
+//
+// The references from and to the fields of a struct that is collapsed
+// according to config are drawn for the struct itself.
+func WriteDot(w io.Writer, r adt.Runtime, v *adt.Vertex, config *DotConfig) {
+	if config == nil {
+		config = &DotConfig{}
+	}
+	g := &dotWriter{
+		p:     printer{Writer: w, index: r, cfg: &Config{}},
+		cfg:   config,
+		ctx:   adt.New(v, &adt.Config{Runtime: r}),
+		ids:   map[*adt.Vertex]string{},
+		edges: map[string]bool{},
+
+		hidden: map[*adt.Vertex]bool{},
+	}
+	fmt.Fprintln(w, "digraph {")
+	fmt.Fprintln(w, "\tnode [shape=box];")
+	g.vertex(v, "<root>")
+	for _, x := range g.refs {
+		g.references(x)
+	}
+	fmt.Fprintln(w, "}")
+}
+
+// DotConfig configures WriteDot.
+type DotConfig struct {
+	// CollapseClosed causes closed structs to be drawn as a single node,
+	// omitting their fields.
+	CollapseClosed bool
+
+	// HighlightErrors causes nodes for errors to be drawn in red and to
+	// include the error message in their label.
+	HighlightErrors bool
+}
+
+type dotWriter struct {
+	p     printer
+	cfg   *DotConfig
+	ctx   *adt.OpContext
+	ids   map[*adt.Vertex]string
+	refs  []*adt.Vertex // vertices for which to draw reference edges
+	edges map[string]bool
+
+	hidden map[*adt.Vertex]bool // vertices drawn as part of a collapsed node
+	nodes  int
+}
+
+func (g *dotWriter) newID() string {
+	g.nodes++
+	return fmt.Sprintf("v%d", g.nodes-1)
+}
+
+// vertex writes the node for v and its arcs and returns its identifier.
+func (g *dotWriter) vertex(v *adt.Vertex, label string) string {
+	if id, ok := g.ids[v]; ok {
+		return id
+	}
+	id := g.newID()
+	g.ids[v] = id
+	g.refs = append(g.refs, v)
+
+	collapse := g.cfg.CollapseClosed && v.IsClosedStruct()
+
+	attrs := []string{"label=" + strconv.Quote(label+"\n"+g.summary(v, collapse))}
+	if _, ok := v.BaseValue.(*adt.Bottom); ok && g.cfg.HighlightErrors {
+		attrs = append(attrs, "color=red", "fontcolor=red")
+	}
+	if collapse {
+		attrs = append(attrs, "style=rounded")
+	}
+	fmt.Fprintf(g.p, "\t%s [%s];\n", id, strings.Join(attrs, ", "))
+
+	if collapse {
+		g.hide(v, id)
+		return id
+	}
+	for _, a := range v.Arcs {
+		label := g.p.labelString(a.Label)
+		to := g.vertex(a, label)
+		g.edge(id, to, label, "")
+	}
+	return id
+}
+
+// summary describes the value of v in a single line.
+func (g *dotWriter) summary(v *adt.Vertex, collapsed bool) string {
+	switch x := v.BaseValue.(type) {
+	case *adt.StructMarker:
+		if collapsed {
+			return fmt.Sprintf("{...} (%d fields)", len(v.Arcs))
+		}
+		return "(struct)"
+	case *adt.ListMarker:
+		return "(list)"
+	case *adt.Bottom:
+		if x.Err != nil && g.cfg.HighlightErrors {
+			return "_|_ // " + strings.ReplaceAll(x.Err.Error(), "\n", " ")
+		}
+		return "_|_"
+	case adt.Node:
+		return NodeString(g.p.index, x, &Config{Compact: true})
+	}
+	return "_"
+}
+
+// references writes edges from v to the vertices referenced by its conjuncts.
+func (g *dotWriter) references(v *adt.Vertex) {
+	from := g.ids[v]
+	_ = dep.Visit(g.ctx, v, func(d dep.Dependency) error {
+		to, ok := g.ids[d.Node]
+		if !ok {
+			to = g.external(d)
+		}
+		if to == from && g.hidden[v] {
+			return nil
+		}
+		var label string
+		if x, ok := d.Reference.(adt.Node); ok {
+			label = NodeString(g.p.index, x, &Config{Compact: true})
+		}
+		g.edge(from, to, label, "dashed")
+		return nil
+	})
+}
+
+// hide associates the descendants of v with the collapsed node id, so that
+// references from and to these descendants are drawn for that node instead.
+func (g *dotWriter) hide(v *adt.Vertex, id string) {
+	for _, a := range v.Arcs {
+		if _, ok := g.ids[a]; ok {
+			continue
+		}
+		g.ids[a] = id
+		g.hidden[a] = true
+		g.refs = append(g.refs, a)
+		g.hide(a, id)
+	}
+}
+
+// external writes a node for a referenced vertex that is not part of the
+// graph and returns its identifier.
+func (g *dotWriter) external(d dep.Dependency) string {
+	id := g.newID()
+	g.ids[d.Node] = id
+
+	var label string
+	if imp := d.Import(); imp != nil {
+		label = imp.ImportPath.StringValue(g.p.index)
+	}
+	var path []string
+	for _, f := range d.Node.Path() {
+		path = append(path, g.p.labelString(f))
+	}
+	if p := strings.Join(path, "."); p != "" {
+		if label != "" {
+			label += "\n"
+		}
+		label += p
+	}
+	fmt.Fprintf(g.p, "\t%s [label=%s, style=dashed];\n", id, strconv.Quote(label))
+	return id
+}
+
+func (g *dotWriter) edge(from, to, label, style string) {
+	key := from + " " + to + " " + label + " " + style
+	if g.edges[key] {
+		return
+	}
+	g.edges[key] = true
+
+	attrs := []string{"label=" + strconv.Quote(label)}
+	if style != "" {
+		attrs = append(attrs, "style="+style)
+	}
+	fmt.Fprintf(g.p, "\t%s -> %s [%s];\n", from, to, strings.Join(attrs, ", "))
+}
@@ -0,0 +1,106 @@
+// Copyright 2022 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug_test
+
+import (
+	"strings"
+	"testing"
+
+	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/internal/core/debug"
+	"cuelang.org/go/internal/value"
+)
+
+func TestWriteDot(t *testing.T) {
+	v := cuecontext.New().CompileString(`
+		import "strings"
+
+		#A: {x: int, y: string}
+		a: #A & {x: b, y: c}
+		b: 1
+		c: strings.ToUpper("c")
+		e: 1 & 2
+		`)
+	r, x := value.ToInternal(v)
+
+	testCases := []struct {
+		name string
+		cfg  *debug.DotConfig
+		want string
+	}{{
+		name: "highlight",
+		cfg:  &debug.DotConfig{HighlightErrors: true},
+		want: `digraph {
+	node [shape=box];
+	v0 [label="<root>\n_|_ // e: conflicting values 2 and 1", color=red, fontcolor=red];
+	v1 [label="#A\n(struct)"];
+	v2 [label="x\nint"];
+	v1 -> v2 [label="x"];
+	v3 [label="y\nstring"];
+	v1 -> v3 [label="y"];
+	v0 -> v1 [label="#A"];
+	v4 [label="a\n(struct)"];
+	v5 [label="x\n1"];
+	v4 -> v5 [label="x"];
+	v6 [label="y\n\"C\""];
+	v4 -> v6 [label="y"];
+	v0 -> v4 [label="a"];
+	v7 [label="b\n1"];
+	v0 -> v7 [label="b"];
+	v8 [label="c\n\"C\""];
+	v0 -> v8 [label="c"];
+	v9 [label="e\n_|_ // e: conflicting values 2 and 1", color=red, fontcolor=red];
+	v0 -> v9 [label="e"];
+	v4 -> v1 [label="#A", style=dashed];
+	v5 -> v7 [label="b", style=dashed];
+	v6 -> v8 [label="c", style=dashed];
+	v10 [label="strings\nToUpper", style=dashed];
+	v8 -> v10 [label="strings.ToUpper", style=dashed];
+}
+`,
+	}, {
+		name: "collapse",
+		cfg:  &debug.DotConfig{CollapseClosed: true},
+		want: `digraph {
+	node [shape=box];
+	v0 [label="<root>\n_|_"];
+	v1 [label="#A\n{...} (2 fields)", style=rounded];
+	v0 -> v1 [label="#A"];
+	v2 [label="a\n{...} (2 fields)", style=rounded];
+	v0 -> v2 [label="a"];
+	v3 [label="b\n1"];
+	v0 -> v3 [label="b"];
+	v4 [label="c\n\"C\""];
+	v0 -> v4 [label="c"];
+	v5 [label="e\n_|_"];
+	v0 -> v5 [label="e"];
+	v2 -> v1 [label="#A", style=dashed];
+	v2 -> v3 [label="b", style=dashed];
+	v2 -> v4 [label="c", style=dashed];
+	v6 [label="strings\nToUpper", style=dashed];
+	v4 -> v6 [label="strings.ToUpper", style=dashed];
+}
+`,
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := &strings.Builder{}
+			debug.WriteDot(w, r, x, tc.cfg)
+			if got := w.String(); got != tc.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2022 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"cuelang.org/go/internal/core/adt"
+)
+
+// A Tracer is an adt.Tracer that writes the steps of an evaluation to a
+// writer. Each step is identified by the path of the vertex being evaluated
+// and the source position of the expression involved, if any.
+//
+// By default, the steps are written as an indented log, where a step that
+// causes a vertex to become an error is followed by that error. If the
+// JSON option is set, each time a step is entered or left a JSON object
+// is written on a single line. Objects for leaving a step additionally hold
+// its duration in nanoseconds, including nested steps, and the error, if
+// any, that the step resulted in.
+type Tracer struct {
+	p      printer
+	cfg    *TraceConfig
+	frames []traceFrame
+}
+
+// TraceConfig configures a Tracer.
+type TraceConfig struct {
+	// Cwd is the directory relative to which file names of positions are
+	// written.
+	Cwd string
+
+	// JSON causes each step to be written as a JSON object instead of as an
+	// indented log.
+	JSON bool
+}
+
+type traceFrame struct {
+	start time.Time
+	err   bool // whether the vertex was an error upon entering the step
+}
+
+// traceEvent is the JSON representation of a trace step.
+type traceEvent struct {
+	Event    string `json:"event"`
+	Kind     string `json:"kind"`
+	Depth    int    `json:"depth"`
+	Path     string `json:"path"`
+	Pos      string `json:"pos,omitempty"`
+	Duration int64  `json:"ns,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// NewTracer returns a Tracer that writes to w. The StringIndexer value i is
+// used to translate labels to strings.
+func NewTracer(w io.Writer, i adt.StringIndexer, config *TraceConfig) *Tracer {
+	if config == nil {
+		config = &TraceConfig{}
+	}
+	return &Tracer{
+		p:   printer{Writer: w, index: i, cfg: &Config{}},
+		cfg: config,
+	}
+}
+
+// Enter implements adt.Tracer.
+func (t *Tracer) Enter(kind adt.TraceKind, v *adt.Vertex, n adt.Node) {
+	depth := len(t.frames)
+	t.frames = append(t.frames, traceFrame{
+		start: time.Now(),
+		err:   t.errString(v) != "",
+	})
+
+	if t.cfg.JSON {
+		t.writeJSON(traceEvent{
+			Event: "enter",
+			Kind:  kind.String(),
+			Depth: depth,
+			Path:  t.path(v),
+			Pos:   t.pos(n),
+		})
+		return
+	}
+
+	indent := strings.Repeat("  ", depth)
+	path := t.path(v)
+	if path == "" {
+		path = "<root>"
+	}
+	fmt.Fprintf(t.p, "%s%s %s", indent, kind, path)
+	if pos := t.pos(n); pos != "" {
+		fmt.Fprintf(t.p, " %s", pos)
+	}
+	fmt.Fprintln(t.p)
+}
+
+// Leave implements adt.Tracer.
+func (t *Tracer) Leave(kind adt.TraceKind, v *adt.Vertex, n adt.Node) {
+	depth := len(t.frames) - 1
+	f := t.frames[depth]
+	t.frames = t.frames[:depth]
+
+	var err string
+	if !f.err {
+		err = t.errString(v)
+	}
+
+	if t.cfg.JSON {
+		t.writeJSON(traceEvent{
+			Event:    "leave",
+			Kind:     kind.String(),
+			Depth:    depth,
+			Path:     t.path(v),
+			Pos:      t.pos(n),
+			Duration: int64(time.Since(f.start)),
+			Error:    err,
+		})
+		return
+	}
+
+	if err != "" {
+		indent := strings.Repeat("  ", depth+1)
+		fmt.Fprintf(t.p, "%s=> _|_ // %s\n", indent, err)
+	}
+}
+
+func (t *Tracer) writeJSON(e traceEvent) {
+	b, _ := json.Marshal(e)
+	b = append(b, '\n')
+	_, _ = t.p.Write(b)
+}
+
+func (t *Tracer) path(v *adt.Vertex) string {
+	if v == nil {
+		return ""
+	}
+	var a []string
+	for _, f := range v.Path() {
+		a = append(a, t.p.labelString(f))
+	}
+	return strings.Join(a, ".")
+}
+
+func (t *Tracer) pos(n adt.Node) string {
+	if n == nil {
+		return ""
+	}
+	src := n.Source()
+	if src == nil || !src.Pos().IsValid() {
+		return ""
+	}
+	pos := src.Pos().Position()
+	if cwd := t.cfg.Cwd; cwd != "" {
+		if rel, err := filepath.Rel(cwd, pos.Filename); err == nil {
+			pos.Filename = rel
+		}
+	}
+	return pos.String()
+}
+
+// errString reports the error of v on a single line, or "" if v is not an
+// error. A vertex that is still being evaluated is not considered to be an
+// error, as its value may be a placeholder.
+func (t *Tracer) errString(v *adt.Vertex) string {
+	if v == nil || v.Status() == adt.Evaluating {
+		return ""
+	}
+	b, ok := v.BaseValue.(*adt.Bottom)
+	if !ok {
+		return ""
+	}
+	if b.Err == nil {
+		return "_|_"
+	}
+	return strings.ReplaceAll(b.Err.Error(), "\n", " ")
+}
@@ -0,0 +1,119 @@
+// Copyright 2022 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"cuelang.org/go/cue/parser"
+	"cuelang.org/go/internal/core/adt"
+	"cuelang.org/go/internal/core/compile"
+	"cuelang.org/go/internal/core/debug"
+	"cuelang.org/go/internal/core/eval"
+	"cuelang.org/go/internal/core/runtime"
+)
+
+func trace(t *testing.T, src string, cfg *debug.TraceConfig) string {
+	r := runtime.New()
+	f, err := parser.ParseFile("in.cue", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, errs := compile.Files(nil, r, "", f)
+	if errs != nil {
+		t.Fatal(errs)
+	}
+	ctx := eval.NewContext(r, v)
+	buf := &bytes.Buffer{}
+	ctx.SetTracer(debug.NewTracer(buf, r, cfg))
+	ctx.Unify(v, adt.Finalized)
+	return buf.String()
+}
+
+func TestTrace(t *testing.T) {
+	got := trace(t, "a: 1\na: 2\n", nil)
+	want := `unify <root>
+  conjunct <root> in.cue:1:1
+  disjunct <root>
+    unify a
+      conjunct a in.cue:1:4
+      conjunct a in.cue:2:4
+      disjunct a
+        => _|_ // a: conflicting values 2 and 1
+      => _|_ // a: conflicting values 2 and 1
+    => _|_ // a: conflicting values 2 and 1
+  => _|_ // a: conflicting values 2 and 1
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestTraceJSON(t *testing.T) {
+	got := trace(t, `
+		a: [for x in [1, 2] {x}]
+		b: *1 | 2
+		`, &debug.TraceConfig{JSON: true})
+
+	type event struct {
+		Event string
+		Kind  string
+		Depth int
+		Path  string
+		Pos   string
+		NS    int64
+	}
+	var stack []event
+	kinds := map[string]bool{}
+	for _, line := range strings.Split(strings.TrimSpace(got), "\n") {
+		var e event
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("invalid JSON %q: %v", line, err)
+		}
+		kinds[e.Kind] = true
+		switch e.Event {
+		case "enter":
+			if e.Depth != len(stack) {
+				t.Errorf("%s: got depth %d; want %d", line, e.Depth, len(stack))
+			}
+			stack = append(stack, e)
+		case "leave":
+			if len(stack) == 0 {
+				t.Fatalf("%s: unbalanced leave", line)
+			}
+			enter := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if enter.Kind != e.Kind || enter.Path != e.Path || enter.Pos != e.Pos {
+				t.Errorf("%s: does not match %+v", line, enter)
+			}
+			if e.NS <= 0 {
+				t.Errorf("%s: no duration", line)
+			}
+		default:
+			t.Errorf("%s: unknown event", line)
+		}
+	}
+	if len(stack) != 0 {
+		t.Errorf("unbalanced enter: %+v", stack)
+	}
+	for _, k := range []string{"unify", "conjunct", "disjunct", "comprehension"} {
+		if !kinds[k] {
+			t.Errorf("no %s steps in trace:\n%s", k, got)
+		}
+	}
+}
@@ -35,6 +35,19 @@ func New(r adt.Runtime) *Unifier {
 	return &Unifier{r: r, e: NewContext(r, nil)}
 }
 
+// TODO: support finalizing independent arcs of a vertex concurrently. This
+// requires more than a dependency check between sibling arcs, as evaluation
+// currently mutates state that is shared between arcs, even if they do not
+// refer to each other:
+//   - StructLit.Init and Comprehension.comp are computed lazily on the
+//     compiled expressions, which are shared by all arcs that unify with, for
+//     instance, the same definition;
+//   - unifying an arc reads the nodeContext of its parent to insert pattern
+//     constraints, and references to ancestors may trigger their evaluation.
+//
+// Each worker would also need its own OpContext, as OpContext is not
+// goroutine safe.
+
 type Unifier struct {
 	r adt.Runtime
 	e *adt.OpContext
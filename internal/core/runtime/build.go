@@ -35,6 +35,13 @@ type Config struct {
 
 	Counts *stats.Counts
 
+	// Limits, if not nil, bounds the resources used for evaluating the
+	// built value.
+	Limits *adt.Limits
+
+	// Profile, if not nil, records the cost of evaluating the built value.
+	Profile *adt.Profile
+
 	compile.Config
 }
 
@@ -129,6 +136,14 @@ func (x *Runtime) buildSpec(cfg *Config, b *build.Instance, spec *ast.ImportSpec
 	}
 
 	pkg := b.LookupImport(info.ID)
+	if pkg == nil && x.importResolver != nil && x.index.builtinPaths[info.ID] == nil {
+		p, err := x.resolveImport(info.ID)
+		if err != nil {
+			return importError(spec.Pos(), info.ID, err)
+		}
+		b.Imports = append(b.Imports, p)
+		return nil
+	}
 	if pkg == nil {
 		if strings.Contains(info.ID, ".") {
 			return errors.Newf(spec.Pos(),
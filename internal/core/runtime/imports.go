@@ -15,11 +15,15 @@
 package runtime
 
 import (
+	"fmt"
 	"path"
 	"sync"
 
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/ast/astutil"
 	"cuelang.org/go/cue/build"
 	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/token"
 	"cuelang.org/go/internal/core/adt"
 )
 
@@ -159,3 +163,79 @@ func (r *Runtime) LoadImport(importPath string) *adt.Vertex {
 
 	return key
 }
+
+// An ImportResolver returns the package for an import path that is not
+// otherwise part of a build. The result is either an evaluated *adt.Vertex or
+// the source of the package as an *ast.File or in any of the forms accepted
+// by build.Instance.AddFile.
+type ImportResolver func(importPath string) (interface{}, error)
+
+type resolvedImport struct {
+	inst *build.Instance
+	err  error
+}
+
+// SetImportResolver sets the function used to resolve imports that cannot
+// otherwise be found.
+func (r *Runtime) SetImportResolver(f ImportResolver) {
+	r.importResolver = f
+}
+
+// resolveImport resolves importPath using the import resolver, if any.
+// Results, including errors, are cached for the lifetime of r, so the
+// resolver is called at most once per import path.
+func (r *Runtime) resolveImport(importPath string) (p *build.Instance, err error) {
+	if res, ok := r.resolved[importPath]; ok {
+		return res.inst, res.err
+	}
+	if r.resolved == nil {
+		r.resolved = map[string]resolvedImport{}
+	}
+	// Guard against packages that (indirectly) import themselves.
+	r.resolved[importPath] = resolvedImport{err: fmt.Errorf("import cycle")}
+	defer func() {
+		r.resolved[importPath] = resolvedImport{p, err}
+	}()
+
+	src, err := r.importResolver(importPath)
+	if err != nil {
+		return nil, err
+	}
+	pkgName := astutil.ImportPathName(importPath)
+
+	if v, ok := src.(*adt.Vertex); ok {
+		p = &build.Instance{
+			ImportPath: importPath,
+			PkgName:    pkgName,
+		}
+		r.AddInst(importPath, v, p)
+		return p, nil
+	}
+
+	p = build.NewContext().NewInstance(importPath, dummyLoad)
+	p.ImportPath = importPath
+	if f, ok := src.(*ast.File); ok {
+		err = p.AddSyntax(f)
+	} else {
+		err = p.AddFile(importPath, src)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if p.PkgName == "" {
+		p.PkgName = pkgName
+	}
+	if _, err := r.Build(nil, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// importError reports an error resolving the import at pos.
+func importError(pos token.Pos, importPath string, err error) errors.Error {
+	if e, ok := err.(errors.Error); ok {
+		// Errors in the resolved package have their own positions.
+		return errors.Append(errors.Newf(pos, "cannot resolve import %q", importPath), e)
+	}
+	return errors.Newf(pos, "cannot resolve import %q: %v", importPath, err)
+}
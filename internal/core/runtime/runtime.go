@@ -23,6 +23,11 @@ type Runtime struct {
 	index *index
 
 	loaded map[*build.Instance]interface{}
+
+	// importResolver, if set, is used to resolve imports that are not part
+	// of a build. The results are cached in resolved.
+	importResolver ImportResolver
+	resolved       map[string]resolvedImport
 }
 
 func (r *Runtime) SetBuildData(b *build.Instance, x interface{}) {
@@ -29,6 +29,11 @@ type Config struct {
 	// AllErrors continues descending into a Vertex, even if errors are found.
 	AllErrors bool
 
+	// MaxDepth, if positive, is the maximum number of levels Validate
+	// descends into a Vertex. Validation stops with an error at the first
+	// value nested deeper than this.
+	MaxDepth int
+
 	// TODO: omitOptional, if this is becomes relevant.
 }
 
@@ -48,6 +53,10 @@ type validator struct {
 	ctx          *adt.OpContext
 	err          *adt.Bottom
 	inDefinition int
+	depth        int
+
+	// tooDeep is set when MaxDepth is exceeded and aborts validation.
+	tooDeep bool
 }
 
 func (v *validator) checkConcrete() bool {
@@ -67,6 +76,15 @@ func (v *validator) add(b *adt.Bottom) {
 func (v *validator) validate(x *adt.Vertex) {
 	defer v.ctx.PopArc(v.ctx.PushArc(x))
 
+	if v.MaxDepth > 0 && v.depth > v.MaxDepth {
+		v.tooDeep = true
+		v.err = adt.CombineErrors(nil, v.err, &adt.Bottom{
+			Code: adt.EvalError,
+			Err:  v.ctx.Newf("maximum depth of %d exceeded", v.MaxDepth),
+		})
+		return
+	}
+
 	if b, _ := x.BaseValue.(*adt.Bottom); b != nil {
 		switch b.Code {
 		case adt.CycleError:
@@ -101,9 +119,10 @@ func (v *validator) validate(x *adt.Vertex) {
 		if a.Label.IsLet() {
 			continue
 		}
-		if !v.AllErrors && v.err != nil {
+		if v.tooDeep || (!v.AllErrors && v.err != nil) {
 			break
 		}
+		v.depth++
 		if a.Label.IsRegular() {
 			v.validate(a)
 		} else {
@@ -111,5 +130,6 @@ func (v *validator) validate(x *adt.Vertex) {
 			v.validate(a)
 			v.inDefinition--
 		}
+		v.depth--
 	}
 }
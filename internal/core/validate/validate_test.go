@@ -181,6 +181,31 @@ y: conflicting values 4 and 2:
 			}
 			`,
 		out: "incomplete\nx.a: incomplete value 1 | 2",
+	}, {
+		desc: "within maximum depth",
+		cfg:  &Config{MaxDepth: 3},
+		in: `
+		a: b: c: 1
+		`,
+	}, {
+		desc: "exceeds maximum depth",
+		cfg:  &Config{MaxDepth: 2, AllErrors: true},
+		in: `
+		a: b: c: d: 1
+		x: y: z: 1
+		`,
+		out: "eval\na.b.c: maximum depth of 2 exceeded",
+	}, {
+		desc: "exceeds maximum depth in definition",
+		cfg:  &Config{MaxDepth: 2},
+		in: `
+		#List: {
+			value: int
+			next?: #List
+		}
+		l: #List & {value: 1, next: value: 2, next: next: value: 3}
+		`,
+		out: "eval\nl.next.value: maximum depth of 2 exceeded",
 	}}
 
 	r := runtime.New()
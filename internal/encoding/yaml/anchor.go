@@ -0,0 +1,155 @@
+// Copyright 2022 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yaml
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// An anchorer replaces repeated subtrees of a YAML node with aliases.
+type anchorer struct {
+	minSize int
+
+	// keys holds the structural key of each collection node.
+	keys map[*yaml.Node]string
+
+	// counts holds the number of occurrences of each structural key.
+	counts map[string]int
+
+	// anchors maps structural keys to the node holding the anchor.
+	anchors map[string]*yaml.Node
+
+	// names holds the anchor names in use.
+	names map[string]bool
+
+	// used records the anchored nodes that are referenced by an alias.
+	used map[*yaml.Node]bool
+}
+
+// addAnchors replaces every repeated mapping or sequence in y consisting of
+// at least minSize nodes with an alias to its first occurrence.
+//
+// Anchor names are derived from the key of the mapping entry in which the
+// first occurrence appears, so that the names are stable across runs.
+func addAnchors(y *yaml.Node, minSize int) {
+	a := &anchorer{
+		minSize: minSize,
+		keys:    map[*yaml.Node]string{},
+		counts:  map[string]int{},
+		anchors: map[string]*yaml.Node{},
+		names:   map[string]bool{},
+		used:    map[*yaml.Node]bool{},
+	}
+	a.count(y)
+	a.replace(y, "")
+
+	// Remove anchors that ended up not being referenced, for instance
+	// because all repetitions were part of a larger aliased subtree.
+	for _, n := range a.anchors {
+		if !a.used[n] {
+			n.Anchor = ""
+		}
+	}
+}
+
+// count computes the structural key of n and its descendants and records the
+// number of occurrences of the keys of sufficiently large collections. It
+// returns the key and the number of nodes in n.
+func (a *anchorer) count(n *yaml.Node) (key string, size int) {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "%d%q%q", n.Kind, n.Tag, n.Value)
+	size = 1
+	if len(n.Content) > 0 {
+		b.WriteByte('(')
+		for _, c := range n.Content {
+			k, s := a.count(c)
+			b.WriteString(k)
+			b.WriteByte(',')
+			size += s
+		}
+		b.WriteByte(')')
+	}
+	key = b.String()
+
+	switch n.Kind {
+	case yaml.MappingNode, yaml.SequenceNode:
+		if size >= a.minSize {
+			a.keys[n] = key
+			a.counts[key]++
+		}
+	}
+	return key, size
+}
+
+// replace anchors the first occurrence of each repeated subtree of n and
+// replaces the subsequent ones with an alias. Label is the mapping key under
+// which n appears, if any.
+func (a *anchorer) replace(n *yaml.Node, label string) {
+	for i, c := range n.Content {
+		if n.Kind == yaml.MappingNode && i%2 == 0 {
+			continue // keys are scalars
+		}
+		if n.Kind == yaml.MappingNode {
+			label = n.Content[i-1].Value
+		}
+
+		key, ok := a.keys[c]
+		if !ok || a.counts[key] < 2 {
+			a.replace(c, label)
+			continue
+		}
+
+		if anchor, ok := a.anchors[key]; ok {
+			a.used[anchor] = true
+			n.Content[i] = &yaml.Node{
+				Kind:        yaml.AliasNode,
+				Value:       anchor.Anchor,
+				Alias:       anchor,
+				HeadComment: c.HeadComment,
+				LineComment: c.LineComment,
+				FootComment: c.FootComment,
+			}
+			continue
+		}
+
+		c.Anchor = a.newName(label)
+		a.anchors[key] = c
+		a.replace(c, label)
+	}
+}
+
+// newName returns a unique anchor name based on the given label.
+func (a *anchorer) newName(label string) string {
+	base := strings.Map(func(r rune) rune {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9',
+			r == '_', r == '-':
+			return r
+		}
+		return '_'
+	}, label)
+	if base == "" {
+		base = "anchor"
+	}
+	name := base
+	for i := 2; a.names[name]; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	a.names[name] = true
+	return name
+}
@@ -46,11 +46,18 @@ import (
 //	CommentGroup
 //
 // TODO: support anchors through Ident.
-func Encode(n ast.Node) (b []byte, err error) {
+func Encode(n ast.Node, opts ...Option) (b []byte, err error) {
+	var cfg config
+	for _, o := range opts {
+		o(&cfg)
+	}
 	y, err := encode(n)
 	if err != nil {
 		return nil, err
 	}
+	if cfg.anchorSize > 0 {
+		addAnchors(y, cfg.anchorSize)
+	}
 	w := &bytes.Buffer{}
 	enc := yaml.NewEncoder(w)
 	// Use idiomatic indentation.
@@ -61,6 +68,21 @@ func Encode(n ast.Node) (b []byte, err error) {
 	return w.Bytes(), nil
 }
 
+// An Option configures the YAML encoding.
+type Option func(c *config)
+
+type config struct {
+	anchorSize int
+}
+
+// Anchors causes structurally identical mappings and sequences consisting of
+// at least minSize nodes to be emitted only once. The first occurrence is
+// marked with an anchor and subsequent occurrences are replaced by an alias.
+// Anchors are disabled if minSize is not positive.
+func Anchors(minSize int) Option {
+	return func(c *config) { c.anchorSize = minSize }
+}
+
 func encode(n ast.Node) (y *yaml.Node, err error) {
 	switch x := n.(type) {
 	case *ast.BasicLit:
@@ -266,6 +266,123 @@ route:
 	}
 }
 
+func TestEncodeAnchors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		minSize int
+		in      string
+		out     string
+	}{{
+		name:    "disabled",
+		minSize: 0,
+		in: `
+		a: {x: 1, y: 2}
+		b: {x: 1, y: 2}
+		`,
+		out: `
+a: {x: 1, "y": 2}
+b: {x: 1, "y": 2}
+		`,
+	}, {
+		name:    "repeated",
+		minSize: 4,
+		in: `
+		a: {
+			x: 1
+			y: 2
+		}
+		b: {
+			x: 1
+			y: 2
+		}
+		c: [{
+			x: 1
+			y: 2
+		}, 3]
+		d: {x: 1}
+		e: {x: 1}
+		`,
+		out: `
+a: &a
+  x: 1
+  "y": 2
+b: *a
+c:
+  - *a
+  - 3
+d: {x: 1}
+e: {x: 1}
+		`,
+	}, {
+		name:    "nested",
+		minSize: 3,
+		in: `
+		"a b": {
+			s: [1, 2]
+			t: {
+				s: [1, 2]
+			}
+		}
+		c: {
+			s: [1, 2]
+			t: {
+				s: [1, 2]
+			}
+		}
+		d: {
+			u: [1, 2]
+		}
+		`,
+		out: `
+a b: &a_b
+  s: &s [1, 2]
+  "t":
+    s: *s
+c: *a_b
+d:
+  u: *s
+		`,
+	}, {
+		name:    "collision",
+		minSize: 2,
+		in: `
+		a: {
+			x: [1]
+			y: [1]
+		}
+		b: {
+			x: [2]
+			y: [2]
+		}
+		`,
+		out: `
+a:
+  x: &x [1]
+  "y": *x
+b:
+  x: &x2 [2]
+  "y": *x2
+		`,
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := parser.ParseFile(tc.name, tc.in, parser.ParseComments)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := Encode(f, Anchors(tc.minSize))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := strings.TrimSpace(string(b))
+			want := strings.TrimSpace(tc.out)
+			if got != want {
+				t.Error(cmp.Diff(got, want))
+			}
+		})
+	}
+}
+
 func TestEncodeAST(t *testing.T) {
 	comment := func(s string) *ast.CommentGroup {
 		return &ast.CommentGroup{List: []*ast.Comment{
@@ -90,7 +90,9 @@ modes: export: {
 		...
 	}
 	#FileInfo: x, let x = {
-		docs:       true | *false
+		// Encodings that cannot represent comments, such as JSON, disable
+		// docs themselves. CUE output keeps doc comments.
+		docs:       *true | false
 		attributes: true | *false
 	}
 	encodings: cue: {
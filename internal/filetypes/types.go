@@ -41,5 +41,5 @@ func cuegenMake(name string, x interface{}) cue.Value {
 	return v
 }
 
-// Data size: 1708 bytes.
-var cuegenInstanceData = []byte("\x01\x1f\x8b\b\x00\x00\x00\x00\x00\x00\xff\xc4X\xddo\xe4\xb6\x11\x97|W\xa0R\xd3>\xe6\xad\xc0\x9c\xae\bR\xe3*#\x1f@\x81\x05\x8cC\u047b+\xee\xa5)\x8a\xf4)\b\f\xae4\xdae#\x91*I%6\xe2}h\x9b\xa6\xfd\xab\xb3\u0150\x94(je\xfb\f\xa4\xa8_\xbc;?\xcepf8\x9f\xfb\xf3\xe3\xbf\xcf\u04b3\xe3\x7f\x92\xf4\xf8\x8f$\xf9\xed\xf1\xefO\xd2\xf4=.\xb4a\xa2\xc2W\xcc0\xa2\xa7O\u04a7\x7f\x96\u04a4gI\xfa\xf4O\xcc\xec\xd3\xf7\x92\xf4'ox\x8b:=~\x9f$\xc9/\x8f\xff:K\xd3_|\xf1e5`\xd9\xf0\xd6s~\x9f\xa4\xc7\xef\x92\xe4\xc3\xe3?\x9f\xa4\xe9O\x03\xfd\xbb$=K\x9f\xfe\x91uH\x82\x9eZb\x9e$\xc9\x0f\xef\xff\x8a4I\u04f34\xcd\xccM\x8f\xba\xac\x06L\x7fx\xffg=\xab\xbeb;\x84\xed\xc0\xdb:\xcf/.\xe0w@\xf7C%\x95B\xddKQk0\x12\x18\xfcA\xbaC%\xc1e\xfe\x9c\xfem\xe0\xdb<\xa3\xeb\x05\xebp\x03\xfeO\x1b\xc5\xc5.\xcfPT\xb2\xe6b7\x01\xcf_{J\x9eqaP\xf5\n\r3\\\x8a\x97\x1bx\xfe6\xa2\xe4Y#U\xf7rb%\xee7Ruyf\xd8N\xbf\xb4\x17g_\xb8\x9b\xbe\xdcLW\x1e\xf2\x835\xe2\x156lh\rp\rf\x8f@*\u00a0\xb1\x86F*\u0426\xe6\x02\x98\xa8\xe9\x93\x1cL\t\x9f\xef\x114\x1a\xc3\xc5NC\x8d=\x8a\x9a\xa4H\x11\xb8;Y\x93\xd5^\xf0\x06\xac\xfd\xf0A\xec\x80\xf3\xe27\x05\u070e\xda\x1cf\xfe|+\x1a\t56\\\xa0\x86\xbd\xfc\x06\x98\x13\xcb5X7am\x15\x9a\u0702\xb5w11Zk\xed\xb7<\xab\x99a\xc1+\xe7F\r\b\xb7\u0430Vc\x9e)lP\xa1\xa8PoN\xc1\xea\xa6j\x1d\xb0\xc2iU\xe3\xe4y:\xb1\x95\xb2\xcd3\xd9\xd3w\xd6:\x16G\xab\xa4\xd0F1.L8\xf7\x15b\xef\xfd\xa27\x9e\xc6E%\xbb\xbeEc\xc3\xc2\u04fa^*3j\xe0h\xda(d\u0768\x94\xa3\u0572\xd2\xc1DGc\xc6(\xbe\x1d\x8c3\xc0\u049c{\xe9]4=\x1e=\x9c\xd3\xc1>r\xcd\x1b\xeb\v\x03\xb2G\u015c%\xeet\x99_\\\x10\xeb\xe7{\xd4\b\x06\xbb\xbee\x0650\x85\xf6\x01\x04\xbd\x86\x91\xb0E\x18\x04o8\u04bb\x0036\x18\x94\x94\x06d\x03f\xcf5\t\xa9\xa4h\xf8np7\x94\xb9\xbd\xc0\xbe\x17\x17\xfd`\\\x9c\xb6h\xe0\x1a.\xed\xe7\u023a\xc5#d\x91\x99K\xf0\x90gY\x88?++d\xd8yQ\rH\xb1wE\xf4\xb2,G\x86\x10C\xd7y`\xd0^@5P\xd4R\xaa\xe9RW{\xec\x98\x17A\xbcxmPh\x17\x12\xf6tQ\xfeUKQ\xf8o\x8b\x1c&\x1d\xd8`\xe4\xa4\xc4\xc1\xb1\u0730\xae},\xcb\xe38\x0e\x94\xf7\x19^St\xcd\x1c~\xf5\u045a\u02fdS\xcfW]\xbe\x04\x1fp\xb9\xf5\xc6\xfd>\xbf\xfa\xe8\x01\xafS>\a\x9f\x1f\xf2L\x0e\xbd\x89\x02\xe7\xea\xe3\x1f\u01ce\xb9V\x1f?V+\xfc\x9a\xea@\xd0\xe9\x93\xff\xb5o\x1f\x0e\xe7\xabO\x1e0\xa2\xe1\x94\xf2s+jl\xe6F|\xfa\xff\xcf\u026bO\x1f\x99\x95c\x87{=&'t\xac\u05ee\x99\x84\x84\xa5\xf2\xe5\u02e1\x83zEe\xd0p\xaa~\x8b\xbc.\x8ay\x97\xbd\u02b3\x82\x86\x83\x89H\xfd\x96\byH\xff@'\xc2\b\xb4\x1e\x99\x80\x96\x90\xb6\x0eL1\"\xeeD|\xc9\b\u0488\x90O\x85a\x050\xd7&\x06\f^\x1b\x02v2Xg\x81\x9d$r\xaf\xa4\x91s}-\xc1J\xc2k3\xa2\x93\xa4\x18\xdd\xcet\x0eh\x9eQK\xf9\xec\xd5g\x1b C4\xfe\xed\x85%\x15\xe5\xc801m\xb9\xe8\xb7pq\x01[.\x98\xba\xe9\xb7\u04e80\x0eH\xc0E\xcd+\u05d5\xdc\x03R40c[\x9b\xc2^\xa1FA\xe3\n0z\u069db]\x99O\xe3\xd5\x06\x9e]\x16\x85\x13) \x1e\xac\xa0F\x83\xaa\x9b\xcd!\x15*\u00f8\x18\xe5\x80\xde\u02e1\xad\xa9\xfbE\xd3\xc8\xc5\x05\xbc\x91\n\xc6\x11\xf6\x05\xd8\x1a\u0471\x9b\xc5I`\u0509u\xa5\xf8\xd6\xe9\xe7\"\xf8\x05|\xb3\xe7\xd5\x1e\xb8\xd1\xd86\xb6s2A\xac\x95\x14_\xa32\xae\xe52\xf8\xfd_^{\x8e2_\u0304\u04d8g'\xc1y\xd0zzcG\xd2hd\x1cG\xaf\u0160V4R\xbahv\x83\xa6\xe3*\xdc\u0145\x7f\x0ez+\x97]\x95\xec:\x1a\xcfZ.\u0411\x8d<\xcd+\x02lF91.\x99\x9d\xf4I2\xa5\xf0N\xb1~\x1f\xa1\x96\xe2\xc0\x9a\xed\"\xa8f\xbb\x110l\x81\x18/\xd0\u058bo\xf3y\xed\xb1\xa5\u01c2d\xe5\t\xeaM\xf7p\xbb\x8a\xb7\xee\x00\xa5\xd8\tn3\xd4\xc26\xf8Op\x97A\xf6\xc0\x94!'\x87B\xaa\xd1A\x9b,\xfd\x96&b;\xa9#7{T\xe4\xe81\x17|\xba\xc0(\xe2\x05\xc8\b\u03f3~\xbb\x81\xf3\xf8\x16\xf7W\x8c\x99V\xe4\xa7#EA\xf7\xc3-\xac1>\xbb\xbc\x9f\u0552\xbd\x95\xab\x06\x16\u04c3Y=\u00a39\xb1'<\x8e|'\xd7\xee\u010d\xde@\xda!\xee2.\x9b\"3\xcbZf\xaf\u0651\xd3}W$\xd6\x1fE\uae05y\xb94\xa89\xfc\x84\xdd\xcep+\x17F3\x95\x8f\xcey6\x9d\b\n\a\xdeE\x9c\xecQ\xb0\x9e\xdf!\u02e3\xef \xc8\xd5\a\u06e0\xa7\xa5\xce7j*\u042cm\x1dX\xc2[\x03\xb5D\rB\x1a\xe0\xa2j\x87\x1a\xddN)U\ao_\x95\xb9=g\x15\xb2\x1b-\xed\xee\x97\xd3Z;\xd5/\xab=5\uaaf5\xea\x02\x93\x96\xde\x15p\v\x85\x9d~\uc9f1\xba,\x96\xad\xe5@\x16\xafl\xcbI'^\x10\x97h\xbc*~\x18\xc1\xbf\x86\x0f\x96\x94<[,\x92Ky\xf1J\xb9D\xe3Er\x81\x1e\xa8\u038bqZ\x9d\x0fQ'\xfe\xf2>:\xb9o\u076a \xff\xa4\x80\x87\ap\xbe&\xafS\xe1v\xffm\xee.\x16w\xd2\xf9\xc4\xe7\ubfbeW\x9b\x85\x1f\xd7\xfd\xb7\xee\xb7`O\xd4stim\x98\xd9\xf6\xec2\x84\xd0\xf8#\u009cy\u0797hu\xd8-\xfd\xf2\xec\u04b7\xb1X\xdbQ\xad\xe8W\x8b\u026e\xf9\xaf\x15\xab\x06\xac\xfae\xd2\xeb\x90\xc7S\xf5\xd4#\xc7$\b\x16\x84\x0e\x19\x96\x9fE\xb6\xb8$\x81\xdb\xf1\xdd\xe6\v\u00e8\xc7|O\b\xc2C\xfb\x8c\x9d\x1b\xa9Ai\xe8$\xc7\x1dyU\x9f\xe9`\xe89\xab\xe7\x82\x0e\xf3V\xf3\xc0Q#\xbb\xfb\xee\x0e\ag-}\x91c\xef4\x06D\xd2\xef\x98\tf/\xb0\xb4\x85\x1a\xfd}b\xe6={MJhy\v\xe5O\f=\xe4q\x9fxD\xad\xb6\u02d4k\x82\xf1-\u02eev\xa7\x03\xef\xed_\xef\u0335\xea\xace4\x1d\xf2$\xf9o\x00\x00\x00\xff\xff\x85i\xbeS\xb4\x16\x00\x00")
+// Data size: 1799 bytes.
+var cuegenInstanceData = []byte("\x01\x1f\x8b\b\x00\x00\x00\x00\x00\x00\xff\xacXK\x8f\xdc\xc6\x11&W\n\x10\x12N\x8e\xbe\x05(S\x80\xe1\f6\x1c\xf8\x81\x1c\x06\x10\x84 \x92\x02\xe5`\x05\x89r\x12\x8cE\x0fY3\xd31\xd9\u0370\x9b\xf2.\xbc\x8b$\x8e\x93\xfc\x8d\xfcR\xafQ\xfd\"\x9b\xc3}\xc1\x92\x0e;S_Wu\xbd\xabz~q\xfd\x9f\x93\xf4\xe4\xfa\xbfIz\xfd\xcf$\xf9\xed?\x1e\xa5\xe9\a\\(\xcdD\x85\u03d9fDN\x1f\xa5\x8f\xff,\xa5NO\x92\xf4\xf1\x9f\x98>\xa4\x1f$\xe9\xcf^\xf2\x06Uz\xfd}\x92$\xbf\xba\xfe\xf7I\x9a\xfe\xf2\xedW\u0540\xe5\x8e7\x8e\xf3\xfb$\xbd\xfe.I>\xb9\xfe\u05e34\xfd\xf9H\xff.IO\xd2\xc7_\xb2\x16I\xd0cC\u0313$\xf9\xe1\xc3\xff\x93\"iz\x92\xa6\x99\xbe\xe8P\x95\u0540\xe9\x0f\x1f\xfe\xafc\xd5\xd7l\x8f\xb0\x1dxS\xe7\xf9z\r\xbf\x03\xba\x1f*\xd9\xf7\xa8:)j\x05Z\x02\x83?H{\xa8$\xb8\u031f\u041f\r|\x9bgt\xbd`-n\xc0\xfdS\xba\xe7b\x9fg(*Ys\xb1\x0f\xc0\x93\x17\x8e\x92g\\h\xec\xbb\x1e5\xd3\\\x8ag\x1bx\xf2*\xa2\xe4\xd9N\xf6\xed\xb3\xc0J\xdc/e\xdf\xe6\x99f{\xf5\xcc\\\x9c\xbd\xb57}\xb5\tW^\xe5W\u0188\xe7\xb8cC\xa3\x81+\xd0\a\x04R\x11\x06\x855\xecd\x0fJ\xd7\\\x00\x135}\x92\x83.\xe1\xcd\x01A\xa1\xd6\\\xec\x15\xd4\u0621\xa8I\x8a\x14#w+k,\xf3'N\xf0\x06\x8c\xfd\xf0q\xec\x80U\xf1\x9b\x02.\xbd6W\x13\x7f\xbe\x12;\t5\xee\xb8@\x05\a\xf9\r0+\x96+0n\xc2\xda(\x14\u0702\xb5s11\x1ak\u0377<\xab\x99f\xa3WV\xba\x1f\x10.a\xc7\x1a\x85y\xd6\xe3\x0e{\x14\x15\xaa\xcd1X]T\x8d\x05\x168\x8dj\x9cbA'\xb6R6y&;\xfa\xce\x1a\xcbbi\x95\x14J\xf7\x8c\v=\x9e\xfb\x1a\xb1s~Q\x1bG\u3892m\u05e06i\xe1hm'{\xed5\xb04\xa5{d\xadW\xca\xd2jY\x055=\x8di\xdd\xf3\xed\xa0\xad\x01\x86f\xddKqQ\x14<\n\x9c\xd5\xc1\x04\xb9\xe6;\xe3\v\r\xb2\xc3\xde\xe4\x14k\xec\xe92_\xaf\x89\xf5\xcd\x01\x15\x82\u01b6k\x98F\x05\xacG\x13\x00QcM9\xbfE\x18\x04\xdfq\xac\x81\xf2E\x9bd\xe8\xa5\xd4 w\xa0\x0f\\\x91\x90J\x8a\x1d\xdf\x0f\xf6\x8627\x17\x98xq\xd1\r\xda|\xca\x1a\xd4p\x0eO\xcd\xe7\u023aY\x10\xb2\xc8\xcc9x\x95g\u0658\x7fF\xd6Xa\xab\xa2\x1a\x90r\xef\x8c\xe8eYz\x861\x87\xce\xf3\x91A9\x01\u0540\x1bXQ\xa9\xa9RU\al\x99\x13A\x97\xe1\xb9F\xa1lJ\x98\xd3E\xf97%E\xe1\xbe\xcdj\x98t`\x83\x96A\t\x12\x91\x15\xe5\x05k\x9b\x87\xb2<\x8c\xe3\x8a\xea>\xc3s\u02ae\x89\xc3\xcf>\xf5._\xaf\xc1w\x1e\xea\aLC\u0144\x90\x1az\xeczT(4T\xb2mQhu\nj\xa8\x0e\xc0\x14\xfc\xf1/\xaf\xbf<\x85\x9a+\xb6m\xd0I\xa1\xd8Q\x16\xb4\n\x9bw\xa8J\xf8\xfd__\x80\x1ct7h\xa0*PP\xcb*\xc8*\x1f\x14n\x87\xad\xee\x19n\x13\x89\xdb\xe3}\xf6\xe9\x1d\x11\xa7^\xe2DX\x1f\u02a1\xd3Q\u049e}\xb6\x94\xb6sU\xef\xb6c\xaa\xd5g\x0f\xd5\n\u07f1f\xaa\xd3\xe7\xefG\xa7\x9fRJg\x9f\xdfa\u010e\v\xd6DV\u0538\x9b\x1a\xf1\u0152\x11\xb7&\xc8\x1c\xfc\xe9F|\xf1\xc0\x8e\xe0\xa7\xeb\v\xdf\x18\xa0e\x9d\xb2\x83ll\x16\xd4:]+\xb6P\xd7S\v\xd6\x1cU\x99\xcfzJQx\xd3\xe9\xffY\x9e\x15\xb4\x98\x04\"\xcdz\"\xe4c\xeb\x19\xe9D\xf0@Slb\xa0!\xa4\xa9G\xa6\x18\x117\"\xae]\x8d\u0488\x90\x87\xa6\xb4\x00\xe8s\x1d\x03\x1a\xcf5q\xece\xa0[`/\x89\xdc\xf5R{\u0110\r\x81\x10b\xf4h\x90\x14\xa3\u06c9\xce#\x9aSsz\xf3\xfa\xf9\xeb\r\x90!\n\xff~jHE\xe9\x19\x02\u04d6\x8bn\v\xeb5l\xb9`\xfdE\xb7\rk\x8ao\x91\xc0E\xcd+;\x11m\x00i>0m\xc6j\xe8\x98\\\xec\x81A\xd7\xcb}\xcf\xda2\x0f\xab\xdd\x06>zZ\x14V\xa4\x80x\xa9\x83\x1a5\xf6\xedd\a\xaa\xb0\u05cc\v/\a\xd4A\x0eM\r[\x8c7\xa1\xf5\x1a^\xca\x1e\xfc\xfa|\n\xa6s\xb5\xecbv\x92\xfav\x8d\xaa\xea\xf9\xd6\xeagg\xda)|s\xe0\xd5\x01\xb8V\xd8\xecH\xb5\x8a\tb\xad\xa4x\x87=1\x9a\x15\x97\xba\xb9\xe5(\xf3\xd9>\x1aVL\xb3\x85\x06\x97\x8e\xdb.9jJ\x86Pi\xf3%\xb1\xd8Ii2\xb1\xb0K\xae\xe5*\xec\u0145\v\a\xc5\xcaV\x17M\x13Z\r\x1b.\u0424\x11\xd5\xd7Q]\x11`*\u028a1\x1f\x9d\xf4 \x99\x1a\xc0\xbeg\xdd!B\r\xa5\xb0-\x8a\xed#\xa8f{\x0f\xe8X$\x11,d6\x88o'\x8dd\x03\xa6\xf5\x18\x90\xac<B\x9d\xe9\x0en\x16\xf1\xc6\x1e\xb8`\xed1ND\v\x9b\xe4?\xc2\r\xd5\x1e\b\x15rt( \xe6\xa0)\x96nK\xcf\x05\xf3J@\xae\x0f\u0613\xa3}-\xb8r\x01/\xe2\x14d\x84\xe7Y\xb7\xdd\xc0*\xbe\x85\xe2\nP\xf8J+\xf2\xe3u\xa6\xa0\xfb\xe1r\xa6\x1e\xb1\x01\x15\u04ad\xac\xddv\xb4r\xd1\xc0\"\x04\x8c\xc4M\x82f\xc5\x1e\xf1X\xf2\x8d\\{\xb9\x81E\x03\xe9\xfdr\x93qY\xc8\xcc,k\x181\x15{Y\x84\xa9H\xac\xefE\xaa+C/\x976Q\x8b\x1f\xb1\x13T,\\\x18\xedT.;\xa7\xd5t$h<p\x1fq\xb2C\xc1:~\x83,\x87\xdeC\x90\xed\x0f\x14 \x15\x1e\x94nPS\x83fMC\x8d\xbaU%\xbc\xd2PKT@\x9b.\x17U3\xd4H\u02eb\x81\xe1\xd5\xf32\xa7\x0f66\xa4\xd3[\xfa\xdd\xe0ixR\x87\xfeebO\x83\xfal\xa9\xbb\xf8\x7f+\xdff\xe0\x12\n\xb3\xfd\x90\u01a1\xbb\xcc\x1ez\xf3\x85,~.\xce7\x9d\xf8q:G\xe3g\xea'\x11\xfck\xf8xN\u0273\xd9#6\x82\xf3l\xf6\x9c\x9d\xa3\xf1#v\x86^Q\x9f\x17~[\x9d.QG\xfer>:\xbao\u066aQ\xfeQ\x03\xf7\x02W\xce\xd7\xe4uj\xdc\xf6\xaf\xa9\xf8\u064f\x06\xa4\xf3\x91\u03d7}}\xab63?.\xfbo\xd9o\x8e:\x9f9\xaa46Ll\xfb\xe8\xe9\x98B\xfe\a\x8c)\xf3t.\xa9\xb2f\xfb\t\xafo\xa2\u4379\xb6NF\xfc\x8b\x89'\xfa\x8b\"c#\x03\x16\xfd\u2234%\xfb\x1a\xb6\xd5\x15f\xa4/\x82pr2!\xc7\xc7\u03ecZV\xe64\\\xfa\xb8M\x1f\fNP\xf4N\x18\x85\x8f\xe33vn\xa4\x06\x95\xa1\x95\xec\xd4in\xd1'\x1c\x1cg\xce\xe2\xb9Q\x87\u9a39\u3a16ms\xaf\x83\x93\x91>\xab\xb1\xb1w\u07b2\x06D\xd2o\xd8\t\u00adpdK\xb7\xbd]\xcctf/I\x19G\xdeLy\x7f8\x1c\xbd\xca\xe39\xf1\x80^m\x1eS4\xe96\x10\xdf2\x9fj3\x1dF;n\x9d_\xf7\xe6Zt\xd6<\x9b\xae\xf2$\xf9q\x00*\x11J\x9a/\x17\x00\x00")
@@ -63,6 +63,12 @@ func Version(minor, patch int) int {
 	return -1000 + 100*minor + patch
 }
 
+// LanguageVersion returns the version of the CUE language supported by this
+// implementation in semantic version format, such as "v0.5.0".
+func LanguageVersion() string {
+	return fmt.Sprintf("v0.%d.0", MinorCurrent)
+}
+
 // ListEllipsis reports the list type and remaining elements of a list. If we
 // ever relax the usage of ellipsis, this function will likely change. Using
 // this function will ensure keeping correct behavior or causing a compiler
@@ -33,7 +33,7 @@ import (
 func Compact(src []byte) (string, error) {
 	dst := bytes.Buffer{}
 	if err := json.Compact(&dst, src); err != nil {
-		return "", err
+		return "", syntaxError(src, err)
 	}
 	return dst.String(), nil
 }
@@ -52,11 +52,32 @@ func Compact(src []byte) (string, error) {
 func Indent(src []byte, prefix, indent string) (string, error) {
 	dst := bytes.Buffer{}
 	if err := json.Indent(&dst, src, prefix, indent); err != nil {
-		return "", err
+		return "", syntaxError(src, err)
 	}
 	return dst.String(), nil
 }
 
+// syntaxError annotates a JSON syntax error with the line and column within
+// src at which it occurred.
+func syntaxError(src []byte, err error) error {
+	se, ok := err.(*json.SyntaxError)
+	if !ok {
+		return err
+	}
+	// Offset is the number of bytes read before the error, so the offending
+	// byte is the last one read.
+	offset := int(se.Offset) - 1
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(src) {
+		offset = len(src)
+	}
+	line := 1 + bytes.Count(src[:offset], []byte("\n"))
+	col := 1 + offset - (bytes.LastIndexByte(src[:offset], '\n') + 1)
+	return fmt.Errorf("json: invalid JSON at line %d, column %d: %v", line, col, se)
+}
+
 // HTMLEscape returns the JSON-encoded src with <, >, &, U+2028 and
 // U+2029 characters inside string literals changed to \u003c, \u003e, \u0026,
 // \u2028, \u2029 so that the JSON will be safe to embed inside HTML <script>
@@ -0,0 +1,116 @@
+// Copyright 2022 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pem
+
+import (
+	"bytes"
+	"encoding/pem"
+	"fmt"
+
+	"cuelang.org/go/cue"
+)
+
+// A Block represents a PEM encoded structure.
+type Block struct {
+	// Type is taken from the preamble, for instance "CERTIFICATE".
+	Type string `json:"type"`
+
+	// Headers holds the optional key-value headers of the block.
+	Headers map[string]string `json:"headers"`
+
+	// Bytes holds the decoded contents of the block.
+	Bytes []byte `json:"bytes"`
+}
+
+// Decode decodes all PEM blocks in data and returns them as a list of
+// structs with fields type, headers, and bytes. Text before, between, and
+// after blocks is ignored. It is an error if data contains no PEM blocks or
+// if any block is malformed.
+func Decode(data []byte) ([]Block, error) {
+	blocks := []Block{}
+	for offset := 0; ; {
+		i := nextBlock(data[offset:])
+		if i < 0 {
+			break
+		}
+		start := offset + i
+
+		// Decode at most one block at a time: pem.Decode skips over
+		// malformed blocks, whereas we want to report them.
+		end := len(data)
+		if j := nextBlock(data[start+1:]); j >= 0 {
+			end = start + 1 + j
+		}
+		p, rest := pem.Decode(data[start:end])
+		if p == nil {
+			line := 1 + bytes.Count(data[:start], []byte("\n"))
+			return nil, fmt.Errorf("malformed PEM block at line %d", line)
+		}
+		if p.Headers == nil {
+			p.Headers = map[string]string{}
+		}
+		blocks = append(blocks, Block{
+			Type:    p.Type,
+			Headers: p.Headers,
+			Bytes:   p.Bytes,
+		})
+		offset = end - len(rest)
+	}
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("no PEM data found")
+	}
+	return blocks, nil
+}
+
+// nextBlock returns the offset of the next line in b that starts a PEM block,
+// or -1 if there is none.
+func nextBlock(b []byte) int {
+	const begin = "-----BEGIN "
+	if bytes.HasPrefix(b, []byte(begin)) {
+		return 0
+	}
+	if i := bytes.Index(b, []byte("\n"+begin)); i >= 0 {
+		return i + 1
+	}
+	return -1
+}
+
+// Encode encodes a list of blocks, each a struct with fields type, headers,
+// and bytes, to PEM. The headers field is optional.
+func Encode(blocks cue.Value) (string, error) {
+	iter, err := blocks.List()
+	if err != nil {
+		return "", err
+	}
+	buf := &bytes.Buffer{}
+	for i := 0; iter.Next(); i++ {
+		var b Block
+		if err := iter.Value().Decode(&b); err != nil {
+			return "", err
+		}
+		if b.Type == "" {
+			return "", fmt.Errorf("block %d: missing type", i)
+		}
+		err := pem.Encode(buf, &pem.Block{
+			Type:    b.Type,
+			Headers: b.Headers,
+			Bytes:   b.Bytes,
+		})
+		if err != nil {
+			return "", fmt.Errorf("block %d: %v", i, err)
+		}
+	}
+	return buf.String(), nil
+}
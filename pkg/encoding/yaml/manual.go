@@ -36,7 +36,9 @@ func Marshal(v cue.Value) (string, error) {
 	return string(b), err
 }
 
-// MarshalStream returns the YAML encoding of v.
+// MarshalStream returns the YAML encoding of v as a multi-document stream.
+// Each element of list v is encoded as a separate document, separated by
+// "---". An empty list results in an empty string.
 func MarshalStream(v cue.Value) (string, error) {
 	// TODO: return an io.Reader and allow asynchronous processing.
 	iter, err := v.List()
@@ -324,8 +324,18 @@ func (g *generator) genFunc(x *ast.FuncDecl) {
 	args := []string{}
 	vals := []string{}
 	kind := []string{}
+	variadic := false
 	for _, f := range x.Type.Params.List {
 		for _, name := range f.Names {
+			if e, ok := f.Type.(*ast.Ellipsis); ok {
+				// A variadic parameter takes all remaining arguments.
+				typ := strings.Title(g.goKind(e.Elt))
+				vals = append(vals, fmt.Sprintf("internal.Variadic(c, %d, c.%s)", len(args), typ))
+				args = append(args, name.Name)
+				kind = append(kind, g.goToCUE(e.Elt))
+				variadic = true
+				continue
+			}
 			typ := strings.Title(g.goKind(f.Type))
 			argKind := g.goToCUE(f.Type)
 			vals = append(vals, fmt.Sprintf("c.%s(%d)", typ, len(args)))
@@ -339,6 +349,9 @@ func (g *generator) genFunc(x *ast.FuncDecl) {
 		fmt.Fprintf(g.w, "{Kind: %s},\n", k)
 	}
 	fmt.Fprintf(g.w, "\n},\n")
+	if variadic {
+		fmt.Fprintf(g.w, "Variadic: true,\n")
+	}
 
 	expr := x.Type.Results.List[0].Type
 	fmt.Fprintf(g.w, "Result: %s,\n", g.goToCUE(expr))
@@ -349,6 +362,9 @@ func (g *generator) genFunc(x *ast.FuncDecl) {
 	if len(args) > 0 {
 		init = fmt.Sprintf("%s := %s", argList, valList)
 	}
+	if variadic {
+		argList += "..."
+	}
 
 	fmt.Fprintf(g.w, "Func: func(c *internal.CallCtxt) {")
 	defer fmt.Fprintln(g.w, "},")
@@ -0,0 +1,38 @@
+// Copyright 2022 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package hash provides non-cryptographic hash functions.
+package hash
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// Bucket returns a bucket index in the range [0, n) for key. It can be used to
+// deterministically distribute entities, such as for sharding.
+//
+// The index is computed as the 64-bit FNV-1a hash of the UTF-8 encoding of key,
+// interpreted as an unsigned integer, modulo n. This algorithm is fixed, so
+// that the bucket assigned to a key only changes if n changes.
+//
+// It is an error if n <= 0.
+func Bucket(key string, n int) (int, error) {
+	if n <= 0 {
+		return 0, fmt.Errorf("number of buckets must be positive, got %d", n)
+	}
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return int(h.Sum64() % uint64(n)), nil
+}
@@ -43,13 +43,17 @@ import (
 //	For any of the above, including interface{} and these types recursively:
 //	[]T
 //	map[string]T
+//
+// If Variadic is set, the last parameter may be passed any number of times,
+// including zero.
 type Builtin struct {
-	Name   string
-	Pkg    adt.Feature
-	Params []Param
-	Result adt.Kind
-	Func   func(c *CallCtxt)
-	Const  string
+	Name     string
+	Pkg      adt.Feature
+	Params   []Param
+	Variadic bool
+	Result   adt.Kind
+	Func     func(c *CallCtxt)
+	Const    string
 }
 
 type Param struct {
@@ -118,10 +122,11 @@ func toBuiltin(ctx *adt.OpContext, b *Builtin) *adt.Builtin {
 	}
 
 	x := &adt.Builtin{
-		Params:  params,
-		Result:  b.Result,
-		Package: b.Pkg,
-		Name:    b.Name,
+		Params:   params,
+		Variadic: b.Variadic,
+		Result:   b.Result,
+		Package:  b.Pkg,
+		Name:     b.Name,
 	}
 	x.Func = func(ctx *adt.OpContext, args []adt.Value) (ret adt.Expr) {
 		// call, _ := ctx.Source().(*ast.CallExpr)
@@ -190,7 +195,7 @@ func (x *Builtin) name(ctx *adt.OpContext) string {
 }
 
 func (x *Builtin) isValidator() bool {
-	return len(x.Params) == 1 && x.Result == adt.BoolKind
+	return len(x.Params) == 1 && !x.Variadic && x.Result == adt.BoolKind
 }
 
 func processErr(call *CallCtxt, errVal interface{}, ret adt.Expr) adt.Expr {
@@ -228,7 +233,13 @@ func processErr(call *CallCtxt, errVal interface{}, ret adt.Expr) adt.Expr {
 			}
 		}
 
-		ret = wrapCallErr(call, &adt.Bottom{Err: err})
+		b := &adt.Bottom{Err: err}
+		// Retain incompleteness of errors wrapped by the builtin.
+		var inner Bottomer
+		if errors.As(err, &inner) && inner.Bottom().IsIncomplete() {
+			b.Code = adt.IncompleteError
+		}
+		ret = wrapCallErr(call, b)
 	case error:
 		if call.Err == internal.ErrIncomplete {
 			err := ctx.NewErrf("incomplete value")
@@ -48,6 +48,16 @@ func (c *CallCtxt) Do() bool {
 	return c.Err == nil
 }
 
+// Variadic returns the arguments from position i onwards, each converted
+// with arg. It is used for the variadic parameter of a builtin.
+func Variadic[T any](c *CallCtxt, i int, arg func(i int) T) []T {
+	var a []T
+	for ; i < len(c.args); i++ {
+		a = append(a, arg(i))
+	}
+	return a
+}
+
 func (c *CallCtxt) Value(i int) cue.Value {
 	v := value.Make(c.ctx, c.args[i])
 	// TODO: remove default
@@ -17,12 +17,13 @@ package list
 
 import (
 	"fmt"
-	"sort"
 
 	"cuelang.org/go/cue"
 	"cuelang.org/go/cue/errors"
 	"cuelang.org/go/cue/token"
 	"cuelang.org/go/internal/core/adt"
+	"cuelang.org/go/internal/core/debug"
+	"cuelang.org/go/internal/value"
 	"cuelang.org/go/pkg/internal"
 )
 
@@ -145,12 +146,34 @@ func Repeat(x []cue.Value, count int) ([]cue.Value, error) {
 	return a, nil
 }
 
-// Concat takes a list of lists and concatenates them.
+// Concat concatenates its list arguments.
 //
-// Concat([a, b, c]) is equivalent to
+// Concat(a, b, c) is equivalent to
 //
 //	[ for x in a {x}, for x in b {x}, for x in c {x} ]
-func Concat(a []cue.Value) ([]cue.Value, error) {
+//
+// Concat() returns the empty list.
+//
+// Note that, for compatibility with earlier versions, in which Concat took a
+// single list of lists, the meaning of Concat depends on the number of
+// arguments: a call with a single argument takes a list of lists. So
+// Concat([a, b, c]) is equivalent to Concat(a, b, c), and Concat([1, 2]) is an
+// error rather than [1, 2], as its elements are not lists. Also,
+// Concat([[1], [2]]) results in [1, 2], whereas Concat([[1], [2]], []) results
+// in [[1], [2]]. Use Concat(x, []) to concatenate a single list x, which may
+// itself contain lists.
+func Concat(a ...[]cue.Value) ([]cue.Value, error) {
+	if len(a) == 1 {
+		return concat(a[0])
+	}
+	res := []cue.Value{}
+	for _, x := range a {
+		res = append(res, x...)
+	}
+	return res, nil
+}
+
+func concat(a []cue.Value) ([]cue.Value, error) {
 	var res []cue.Value
 	for _, e := range a {
 		iter, err := e.List()
@@ -215,6 +238,34 @@ func Slice(x []cue.Value, i, j int) ([]cue.Value, error) {
 	return x[i:j], nil
 }
 
+// Reverse returns the elements of list x in reverse order.
+//
+// For instance:
+//
+//	Reverse([1, 2, 3])
+//
+// results in
+//
+//	[3, 2, 1]
+//
+// The elements themselves need not be concrete, but the length of x must be
+// known: reversing an open list, such as [1, ...int], results in an
+// incomplete error.
+func Reverse(x cue.Value) ([]cue.Value, error) {
+	iter, err := x.List()
+	if err != nil {
+		return nil, err
+	}
+	var res []cue.Value
+	for iter.Next() {
+		res = append(res, iter.Value())
+	}
+	for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+		res[i], res[j] = res[j], res[i]
+	}
+	return res, nil
+}
+
 // MinItems reports whether a has at least n items.
 func MinItems(list internal.List, n int) (bool, error) {
 	count := len(list.Elems())
@@ -227,7 +278,7 @@ func MinItems(list internal.List, n int) (bool, error) {
 	}
 	return false, internal.ValidationError{B: &adt.Bottom{
 		Code: code,
-		Err:  errors.Newf(token.NoPos, "len(list) < MinItems(%[2]d) (%[1]d < %[2]d)", count, n),
+		Err:  errors.Newf(token.NoPos, "list must have at least %d items, got %d", n, count),
 	}}
 }
 
@@ -237,7 +288,7 @@ func MaxItems(list internal.List, n int) (bool, error) {
 	if count > n {
 		return false, internal.ValidationError{B: &adt.Bottom{
 			Code: adt.EvalError,
-			Err:  errors.Newf(token.NoPos, "len(list) > MaxItems(%[2]d) (%[1]d > %[2]d)", count, n),
+			Err:  errors.Newf(token.NoPos, "list must have at most %d items, got %d", n, count),
 		}}
 	}
 
@@ -245,18 +296,65 @@ func MaxItems(list internal.List, n int) (bool, error) {
 }
 
 // UniqueItems reports whether all elements in the list are unique.
-func UniqueItems(a []cue.Value) bool {
-	b := []string{}
-	for _, v := range a {
-		b = append(b, fmt.Sprintf("%+v", v))
+//
+// If the list contains duplicates, the error reports the duplicated value and
+// the indices of its first two occurrences.
+func UniqueItems(a []cue.Value) (bool, error) {
+	seen := map[string]int{}
+	for i, v := range a {
+		s := fmt.Sprintf("%+v", v)
+		if j, ok := seen[s]; ok {
+			return false, duplicateError(fmt.Sprintf("value %s", compact(v)), j, i)
+		}
+		seen[s] = i
 	}
-	sort.Strings(b)
-	for i := 1; i < len(b); i++ {
-		if b[i-1] == b[i] {
-			return false
+	return true, nil
+}
+
+// UniqueItemsBy reports whether key yields a different value for each of the
+// elements in the list.
+//
+// See IndexOf for the form of key. If the list contains elements with the
+// same key, the error reports the duplicated key and the indices of the
+// first two elements for which it occurs, as well as the second of these
+// elements.
+//
+// Example:
+//
+//	UniqueItemsBy([{id: 1}, {id: 2}], {x: _, key: x.id})
+//
+// results in
+//
+//	true
+func UniqueItemsBy(a []cue.Value, key cue.Value) (bool, error) {
+	seen := map[string]int{}
+	for i, v := range a {
+		k, err := elemKey(key, v)
+		if err != nil {
+			return false, err
+		}
+		s := fmt.Sprintf("%+v", k)
+		if j, ok := seen[s]; ok {
+			return false, duplicateError(
+				fmt.Sprintf("key %s of element %s", compact(k), compact(v)), j, i)
 		}
+		seen[s] = i
 	}
-	return true
+	return true, nil
+}
+
+// duplicateError reports that what occurs at indices i and j.
+func duplicateError(what string, i, j int) error {
+	return internal.ValidationError{B: &adt.Bottom{
+		Code: adt.EvalError,
+		Err:  errors.Newf(token.NoPos, "duplicate %s at indices %d and %d", what, i, j),
+	}}
+}
+
+// compact formats v on a single line, as is done for values in error messages.
+func compact(v cue.Value) string {
+	r, x := value.ToInternal(v)
+	return debug.NodeString(r, x, &debug.Config{Compact: true})
 }
 
 // Contains reports whether v is contained in a. The value must be a
@@ -269,3 +367,52 @@ func Contains(a []cue.Value, v cue.Value) bool {
 	}
 	return false
 }
+
+// IndexOf reports the index of the first element in a for which key yields
+// the same value as for v, or -1 if there is no such element.
+//
+// key is a struct of the form {x: _, key: _}, where key is computed from x.
+// An incomplete error is reported if key cannot be computed for v or for any
+// of the elements visited.
+//
+// Example:
+//
+//	IndexOf([{id: 1}, {id: 2}], {id: 2}, {x: _, key: x.id})
+//
+// results in
+//
+//	1
+func IndexOf(a []cue.Value, v cue.Value, key cue.Value) (int, error) {
+	want, err := elemKey(key, v)
+	if err != nil {
+		return 0, err
+	}
+	for i, w := range a {
+		k, err := elemKey(key, w)
+		if err != nil {
+			return 0, err
+		}
+		if k.Equals(want) {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
+
+// ContainsBy reports whether a contains an element for which key yields the
+// same value as for v.
+//
+// See IndexOf for the form of key.
+func ContainsBy(a []cue.Value, v cue.Value, key cue.Value) (bool, error) {
+	i, err := IndexOf(a, v, key)
+	return i >= 0, err
+}
+
+// elemKey computes the key for x using a struct of the form {x: _, key: _}.
+func elemKey(key, x cue.Value) (cue.Value, error) {
+	k := key.FillPath(cue.ParsePath("x"), x).LookupPath(cue.ParsePath("key"))
+	if err := k.Validate(cue.Concrete(true)); err != nil {
+		return k, err
+	}
+	return k, nil
+}
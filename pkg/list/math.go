@@ -19,10 +19,14 @@ import (
 
 	"github.com/cockroachdb/apd/v2"
 
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/token"
 	"cuelang.org/go/internal"
 )
 
-// Avg returns the average value of a non empty list xs.
+// Avg returns the average value of a non empty list xs. It is an error for xs
+// to be empty.
 func Avg(xs []*internal.Decimal) (*internal.Decimal, error) {
 	if 0 == len(xs) {
 		return nil, fmt.Errorf("empty list")
@@ -75,7 +79,8 @@ func Min(xs []*internal.Decimal) (*internal.Decimal, error) {
 	return min, nil
 }
 
-// Product returns the product of a non empty list xs.
+// Product returns the product of the numbers in xs, or 1 if xs is empty.
+// The product is computed using arbitrary-precision decimal arithmetic.
 func Product(xs []*internal.Decimal) (*internal.Decimal, error) {
 	d := apd.New(1, 0)
 	for _, x := range xs {
@@ -132,7 +137,9 @@ func Range(start, limit, step *internal.Decimal) ([]*internal.Decimal, error) {
 	return vals, nil
 }
 
-// Sum returns the sum of a list non empty xs.
+// Sum returns the sum of the numbers in xs, or 0 if xs is empty. The sum is
+// computed using arbitrary-precision decimal arithmetic, so that adding many
+// decimal numbers does not accumulate rounding errors.
 func Sum(xs []*internal.Decimal) (*internal.Decimal, error) {
 	d := apd.New(0, 0)
 	for _, x := range xs {
@@ -143,3 +150,84 @@ func Sum(xs []*internal.Decimal) (*internal.Decimal, error) {
 	}
 	return d, nil
 }
+
+// SumBy returns the sum of the numbers at path in each element of xs, or 0 if
+// xs is empty. As with Sum, the sum is computed using arbitrary-precision
+// decimal arithmetic.
+//
+// For instance:
+//
+//	SumBy([{n: 1}, {n: 2.5}], "n")
+//
+// results in
+//
+//	3.5
+func SumBy(xs []cue.Value, path string) (*internal.Decimal, error) {
+	p := cue.ParsePath(path)
+	if err := p.Err(); err != nil {
+		return nil, fmt.Errorf("invalid path %q: %v", path, err)
+	}
+	d := apd.New(0, 0)
+	for i, x := range xs {
+		k, err := numKey(x, i, p)
+		if err != nil {
+			return nil, err
+		}
+		_, err = internal.BaseContext.Add(d, k, d)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// MaxBy returns the first element of the non-empty list xs for which the
+// number at path is the largest.
+func MaxBy(xs []cue.Value, path string) (cue.Value, error) {
+	return extremeBy(xs, path, -1)
+}
+
+// MinBy returns the first element of the non-empty list xs for which the
+// number at path is the smallest.
+func MinBy(xs []cue.Value, path string) (cue.Value, error) {
+	return extremeBy(xs, path, +1)
+}
+
+// extremeBy returns the first element of xs with the largest key if cmp is -1,
+// or with the smallest key if cmp is +1.
+func extremeBy(xs []cue.Value, path string, cmp int) (cue.Value, error) {
+	if 0 == len(xs) {
+		return cue.Value{}, fmt.Errorf("empty list")
+	}
+	p := cue.ParsePath(path)
+	if err := p.Err(); err != nil {
+		return cue.Value{}, fmt.Errorf("invalid path %q: %v", path, err)
+	}
+	best, bestKey := 0, (*internal.Decimal)(nil)
+	for i, x := range xs {
+		k, err := numKey(x, i, p)
+		if err != nil {
+			return cue.Value{}, err
+		}
+		if bestKey == nil || cmp == bestKey.Cmp(k) {
+			best, bestKey = i, k
+		}
+	}
+	return xs[best], nil
+}
+
+// numKey returns the number at path p in x, which is the element at index i.
+func numKey(x cue.Value, i int, p cue.Path) (*internal.Decimal, error) {
+	k := x.LookupPath(p)
+	if !k.Exists() {
+		return nil, fmt.Errorf("element %d: field %v not found", i, p)
+	}
+	if err := k.Validate(cue.Concrete(true)); err != nil {
+		return nil, errors.Wrapf(err, token.NoPos, "element %d", i)
+	}
+	if k.Kind()&cue.NumberKind == 0 {
+		return nil, fmt.Errorf("element %d: field %v: cannot use value %v (type %v) as number",
+			i, p, k, k.Kind())
+	}
+	return k.Decimal()
+}
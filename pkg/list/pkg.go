@@ -58,11 +58,12 @@ var pkg = &internal.Package{
 		Params: []internal.Param{
 			{Kind: adt.ListKind},
 		},
-		Result: adt.ListKind,
+		Variadic: true,
+		Result:   adt.ListKind,
 		Func: func(c *internal.CallCtxt) {
-			a := c.List(0)
+			a := internal.Variadic(c, 0, c.List)
 			if c.Do() {
-				c.Ret, c.Err = Concat(a)
+				c.Ret, c.Err = Concat(a...)
 			}
 		},
 	}, {
@@ -92,6 +93,18 @@ var pkg = &internal.Package{
 				c.Ret, c.Err = Slice(x, i, j)
 			}
 		},
+	}, {
+		Name: "Reverse",
+		Params: []internal.Param{
+			{Kind: adt.TopKind},
+		},
+		Result: adt.ListKind,
+		Func: func(c *internal.CallCtxt) {
+			x := c.Value(0)
+			if c.Do() {
+				c.Ret, c.Err = Reverse(x)
+			}
+		},
 	}, {
 		Name: "MinItems",
 		Params: []internal.Param{
@@ -127,7 +140,20 @@ var pkg = &internal.Package{
 		Func: func(c *internal.CallCtxt) {
 			a := c.List(0)
 			if c.Do() {
-				c.Ret = UniqueItems(a)
+				c.Ret, c.Err = UniqueItems(a)
+			}
+		},
+	}, {
+		Name: "UniqueItemsBy",
+		Params: []internal.Param{
+			{Kind: adt.ListKind},
+			{Kind: adt.TopKind},
+		},
+		Result: adt.BoolKind,
+		Func: func(c *internal.CallCtxt) {
+			a, key := c.List(0), c.Value(1)
+			if c.Do() {
+				c.Ret, c.Err = UniqueItemsBy(a, key)
 			}
 		},
 	}, {
@@ -143,6 +169,34 @@ var pkg = &internal.Package{
 				c.Ret = Contains(a, v)
 			}
 		},
+	}, {
+		Name: "IndexOf",
+		Params: []internal.Param{
+			{Kind: adt.ListKind},
+			{Kind: adt.TopKind},
+			{Kind: adt.TopKind},
+		},
+		Result: adt.IntKind,
+		Func: func(c *internal.CallCtxt) {
+			a, v, key := c.List(0), c.Value(1), c.Value(2)
+			if c.Do() {
+				c.Ret, c.Err = IndexOf(a, v, key)
+			}
+		},
+	}, {
+		Name: "ContainsBy",
+		Params: []internal.Param{
+			{Kind: adt.ListKind},
+			{Kind: adt.TopKind},
+			{Kind: adt.TopKind},
+		},
+		Result: adt.BoolKind,
+		Func: func(c *internal.CallCtxt) {
+			a, v, key := c.List(0), c.Value(1), c.Value(2)
+			if c.Do() {
+				c.Ret, c.Err = ContainsBy(a, v, key)
+			}
+		},
 	}, {
 		Name: "Avg",
 		Params: []internal.Param{
@@ -217,6 +271,45 @@ var pkg = &internal.Package{
 				c.Ret, c.Err = Sum(xs)
 			}
 		},
+	}, {
+		Name: "SumBy",
+		Params: []internal.Param{
+			{Kind: adt.ListKind},
+			{Kind: adt.StringKind},
+		},
+		Result: adt.NumKind,
+		Func: func(c *internal.CallCtxt) {
+			xs, path := c.List(0), c.String(1)
+			if c.Do() {
+				c.Ret, c.Err = SumBy(xs, path)
+			}
+		},
+	}, {
+		Name: "MaxBy",
+		Params: []internal.Param{
+			{Kind: adt.ListKind},
+			{Kind: adt.StringKind},
+		},
+		Result: adt.TopKind,
+		Func: func(c *internal.CallCtxt) {
+			xs, path := c.List(0), c.String(1)
+			if c.Do() {
+				c.Ret, c.Err = MaxBy(xs, path)
+			}
+		},
+	}, {
+		Name: "MinBy",
+		Params: []internal.Param{
+			{Kind: adt.ListKind},
+			{Kind: adt.StringKind},
+		},
+		Result: adt.TopKind,
+		Func: func(c *internal.CallCtxt) {
+			xs, path := c.List(0), c.String(1)
+			if c.Do() {
+				c.Ret, c.Err = MinBy(xs, path)
+			}
+		},
 	}, {
 		Name: "Sort",
 		Params: []internal.Param{
@@ -15,10 +15,12 @@
 package math
 
 import (
+	"fmt"
 	"math/big"
 
 	"github.com/cockroachdb/apd/v2"
 
+	"cuelang.org/go/cue"
 	"cuelang.org/go/internal"
 )
 
@@ -121,3 +123,43 @@ func MultipleOf(x, y *internal.Decimal) (bool, error) {
 	cond, err := mulContext.Quo(&d, x, y)
 	return !cond.Inexact(), err
 }
+
+// Clamp returns x bounded to the interval [lo, hi]: it returns lo if x < lo,
+// hi if x > hi, and x otherwise. The result retains the type, int or float, of
+// the argument it is taken from.
+//
+// It is an error if lo > hi.
+func Clamp(x, lo, hi cue.Value) (cue.Value, error) {
+	dx, err := toDecimal(x)
+	if err != nil {
+		return cue.Value{}, err
+	}
+	dlo, err := toDecimal(lo)
+	if err != nil {
+		return cue.Value{}, err
+	}
+	dhi, err := toDecimal(hi)
+	if err != nil {
+		return cue.Value{}, err
+	}
+	if dlo.Cmp(dhi) > 0 {
+		return cue.Value{}, fmt.Errorf("lower bound %v exceeds upper bound %v", dlo, dhi)
+	}
+	switch {
+	case dx.Cmp(dlo) < 0:
+		return lo, nil
+	case dx.Cmp(dhi) > 0:
+		return hi, nil
+	}
+	return x, nil
+}
+
+// toDecimal returns the exact value of the number v.
+func toDecimal(v cue.Value) (*apd.Decimal, error) {
+	var mant big.Int
+	exp, err := v.MantExp(&mant)
+	if err != nil {
+		return nil, err
+	}
+	return apd.NewWithBigInt(&mant, int32(exp)), nil
+}
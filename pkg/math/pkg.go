@@ -139,6 +139,20 @@ var pkg = &internal.Package{
 				c.Ret, c.Err = MultipleOf(x, y)
 			}
 		},
+	}, {
+		Name: "Clamp",
+		Params: []internal.Param{
+			{Kind: adt.TopKind},
+			{Kind: adt.TopKind},
+			{Kind: adt.TopKind},
+		},
+		Result: adt.TopKind,
+		Func: func(c *internal.CallCtxt) {
+			x, lo, hi := c.Value(0), c.Value(1), c.Value(2)
+			if c.Do() {
+				c.Ret, c.Err = Clamp(x, lo, hi)
+			}
+		},
 	}, {
 		Name: "Abs",
 		Params: []internal.Param{
@@ -23,4 +23,7 @@
 //
 //	// unicast defines a global unicast IP address in list form.
 //	unicast: net.GlobalUnicastIP & [...]
+//
+// The functions classifying IP addresses, such as LoopbackIP and PrivateIP,
+// fail if the given value is not a valid IP address.
 package net
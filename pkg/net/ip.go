@@ -75,6 +75,16 @@ func netGetIP(ip cue.Value) (goip net.IP) {
 	}
 }
 
+// netGetValidIP is like netGetIP, but returns an error if ip is not a valid
+// IP address.
+func netGetValidIP(ip cue.Value) (net.IP, error) {
+	goip := netGetIP(ip)
+	if len(goip) != IPv4len && len(goip) != IPv6len {
+		return nil, fmt.Errorf("invalid IP %q", ip)
+	}
+	return goip, nil
+}
+
 func netGetIPCIDR(ip cue.Value) (gonet *net.IPNet, err error) {
 	switch ip.Kind() {
 	case cue.StringKind:
@@ -151,29 +161,49 @@ func IPCIDR(ip cue.Value) (bool, error) {
 }
 
 // LoopbackIP reports whether ip is a loopback address.
-func LoopbackIP(ip cue.Value) bool {
-	return netGetIP(ip).IsLoopback()
+func LoopbackIP(ip cue.Value) (bool, error) {
+	ipdata, err := netGetValidIP(ip)
+	if err != nil {
+		return false, err
+	}
+	return ipdata.IsLoopback(), nil
 }
 
 // MulticastIP reports whether ip is a multicast address.
-func MulticastIP(ip cue.Value) bool {
-	return netGetIP(ip).IsMulticast()
+func MulticastIP(ip cue.Value) (bool, error) {
+	ipdata, err := netGetValidIP(ip)
+	if err != nil {
+		return false, err
+	}
+	return ipdata.IsMulticast(), nil
 }
 
 // InterfaceLocalMulticastIP reports whether ip is an interface-local multicast
 // address.
-func InterfaceLocalMulticastIP(ip cue.Value) bool {
-	return netGetIP(ip).IsInterfaceLocalMulticast()
+func InterfaceLocalMulticastIP(ip cue.Value) (bool, error) {
+	ipdata, err := netGetValidIP(ip)
+	if err != nil {
+		return false, err
+	}
+	return ipdata.IsInterfaceLocalMulticast(), nil
 }
 
 // LinkLocalMulticast reports whether ip is a link-local multicast address.
-func LinkLocalMulticastIP(ip cue.Value) bool {
-	return netGetIP(ip).IsLinkLocalMulticast()
+func LinkLocalMulticastIP(ip cue.Value) (bool, error) {
+	ipdata, err := netGetValidIP(ip)
+	if err != nil {
+		return false, err
+	}
+	return ipdata.IsLinkLocalMulticast(), nil
 }
 
 // LinkLocalUnicastIP reports whether ip is a link-local unicast address.
-func LinkLocalUnicastIP(ip cue.Value) bool {
-	return netGetIP(ip).IsLinkLocalUnicast()
+func LinkLocalUnicastIP(ip cue.Value) (bool, error) {
+	ipdata, err := netGetValidIP(ip)
+	if err != nil {
+		return false, err
+	}
+	return ipdata.IsLinkLocalUnicast(), nil
 }
 
 // GlobalUnicastIP reports whether ip is a global unicast address.
@@ -182,14 +212,32 @@ func LinkLocalUnicastIP(ip cue.Value) bool {
 // identification as defined in RFC 1122, RFC 4632 and RFC 4291 with the
 // exception of IPv4 directed broadcast addresses. It returns true even if ip is
 // in IPv4 private address space or local IPv6 unicast address space.
-func GlobalUnicastIP(ip cue.Value) bool {
-	return netGetIP(ip).IsGlobalUnicast()
+func GlobalUnicastIP(ip cue.Value) (bool, error) {
+	ipdata, err := netGetValidIP(ip)
+	if err != nil {
+		return false, err
+	}
+	return ipdata.IsGlobalUnicast(), nil
+}
+
+// PrivateIP reports whether ip is a private address, according to RFC 1918
+// (IPv4 addresses) and RFC 4193 (IPv6 addresses).
+func PrivateIP(ip cue.Value) (bool, error) {
+	ipdata, err := netGetValidIP(ip)
+	if err != nil {
+		return false, err
+	}
+	return ipdata.IsPrivate(), nil
 }
 
 // UnspecifiedIP reports whether ip is an unspecified address, either the IPv4
 // address "0.0.0.0" or the IPv6 address "::".
-func UnspecifiedIP(ip cue.Value) bool {
-	return netGetIP(ip).IsUnspecified()
+func UnspecifiedIP(ip cue.Value) (bool, error) {
+	ipdata, err := netGetValidIP(ip)
+	if err != nil {
+		return false, err
+	}
+	return ipdata.IsUnspecified(), nil
 }
 
 // ToIP4 converts a given IP address, which may be a string or a list, to its
@@ -114,7 +114,7 @@ var pkg = &internal.Package{
 		Func: func(c *internal.CallCtxt) {
 			ip := c.Value(0)
 			if c.Do() {
-				c.Ret = LoopbackIP(ip)
+				c.Ret, c.Err = LoopbackIP(ip)
 			}
 		},
 	}, {
@@ -126,7 +126,7 @@ var pkg = &internal.Package{
 		Func: func(c *internal.CallCtxt) {
 			ip := c.Value(0)
 			if c.Do() {
-				c.Ret = MulticastIP(ip)
+				c.Ret, c.Err = MulticastIP(ip)
 			}
 		},
 	}, {
@@ -138,7 +138,7 @@ var pkg = &internal.Package{
 		Func: func(c *internal.CallCtxt) {
 			ip := c.Value(0)
 			if c.Do() {
-				c.Ret = InterfaceLocalMulticastIP(ip)
+				c.Ret, c.Err = InterfaceLocalMulticastIP(ip)
 			}
 		},
 	}, {
@@ -150,7 +150,7 @@ var pkg = &internal.Package{
 		Func: func(c *internal.CallCtxt) {
 			ip := c.Value(0)
 			if c.Do() {
-				c.Ret = LinkLocalMulticastIP(ip)
+				c.Ret, c.Err = LinkLocalMulticastIP(ip)
 			}
 		},
 	}, {
@@ -162,7 +162,7 @@ var pkg = &internal.Package{
 		Func: func(c *internal.CallCtxt) {
 			ip := c.Value(0)
 			if c.Do() {
-				c.Ret = LinkLocalUnicastIP(ip)
+				c.Ret, c.Err = LinkLocalUnicastIP(ip)
 			}
 		},
 	}, {
@@ -174,7 +174,19 @@ var pkg = &internal.Package{
 		Func: func(c *internal.CallCtxt) {
 			ip := c.Value(0)
 			if c.Do() {
-				c.Ret = GlobalUnicastIP(ip)
+				c.Ret, c.Err = GlobalUnicastIP(ip)
+			}
+		},
+	}, {
+		Name: "PrivateIP",
+		Params: []internal.Param{
+			{Kind: adt.TopKind},
+		},
+		Result: adt.BoolKind,
+		Func: func(c *internal.CallCtxt) {
+			ip := c.Value(0)
+			if c.Do() {
+				c.Ret, c.Err = PrivateIP(ip)
 			}
 		},
 	}, {
@@ -186,7 +198,7 @@ var pkg = &internal.Package{
 		Func: func(c *internal.CallCtxt) {
 			ip := c.Value(0)
 			if c.Do() {
-				c.Ret = UnspecifiedIP(ip)
+				c.Ret, c.Err = UnspecifiedIP(ip)
 			}
 		},
 	}, {
@@ -25,7 +25,9 @@ import (
 	_ "cuelang.org/go/pkg/encoding/csv"
 	_ "cuelang.org/go/pkg/encoding/hex"
 	_ "cuelang.org/go/pkg/encoding/json"
+	_ "cuelang.org/go/pkg/encoding/pem"
 	_ "cuelang.org/go/pkg/encoding/yaml"
+	_ "cuelang.org/go/pkg/hash"
 	_ "cuelang.org/go/pkg/html"
 
 	_ "cuelang.org/go/pkg/list"
@@ -29,6 +29,9 @@ import (
 	"fmt"
 	"strings"
 	"unicode"
+	"unicode/utf8"
+
+	"cuelang.org/go/cue"
 )
 
 // ByteAt reports the ith byte of the underlying strings or byte.
@@ -53,6 +56,23 @@ func Runes(s string) []rune {
 	return []rune(s)
 }
 
+// RuneLen reports the number of runes (Unicode code points) in s.
+//
+// Note that the builtin len reports the number of bytes of a string, which
+// differs from the number of runes for strings containing characters outside
+// the ASCII range. For instance, len("héllo") is 6, whereas RuneLen("héllo")
+// is 5.
+func RuneLen(s string) int {
+	return utf8.RuneCountInString(s)
+}
+
+// ByteLen reports the number of bytes in the UTF-8 encoding of a string or the
+// length of a bytes value. It is equivalent to the builtin len, but makes
+// explicit which notion of length is intended.
+func ByteLen(b []byte) int {
+	return len(b)
+}
+
 // MinRunes reports whether the number of runes (Unicode codepoints) in a string
 // is at least a certain minimum. MinRunes can be used a a field constraint to
 // except all strings for which this property holds.
@@ -118,3 +138,133 @@ func SliceRunes(s string, start, end int) (string, error) {
 	}
 	return string(runes[start:end]), nil
 }
+
+// SplitAny slices s into all substrings separated by any of the Unicode code
+// points in chars and returns a slice of the substrings between those
+// separators.
+//
+// As with Split, consecutive separators result in empty substrings. Use Fields
+// to split around runs of white space instead. If chars is empty or s does not
+// contain any of the code points in chars, SplitAny returns a slice of length
+// 1 whose only element is s.
+func SplitAny(s, chars string) []string {
+	a := []string{}
+	start := 0
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if strings.ContainsRune(chars, r) {
+			a = append(a, s[start:i])
+			start = i + size
+		}
+		i += size
+	}
+	return append(a, s[start:])
+}
+
+// TrimAnyPrefix returns s without the longest of the given prefixes that s
+// starts with. If s starts with none of the prefixes, or prefixes is empty,
+// s is returned unchanged.
+func TrimAnyPrefix(s string, prefixes []string) string {
+	longest := -1
+	for _, p := range prefixes {
+		if len(p) > longest && strings.HasPrefix(s, p) {
+			longest = len(p)
+		}
+	}
+	if longest < 0 {
+		return s
+	}
+	return s[longest:]
+}
+
+// Translate returns a copy of s in which each character that is a field name
+// of m is replaced with the string value of that field. Characters mapped to
+// the empty string are removed, and characters not in m are left unchanged.
+// Each field name of m must consist of a single character.
+//
+// For instance,
+//
+//	strings.Translate("a-b c!", {"-": "_", " ": "", "!": "?"})
+//
+// results in "a_bc?".
+func Translate(s string, m cue.Value) (string, error) {
+	iter, err := m.Fields()
+	if err != nil {
+		return "", err
+	}
+	table := map[rune]string{}
+	for iter.Next() {
+		key := iter.Label()
+		r, size := utf8.DecodeRuneInString(key)
+		if size == 0 || size != len(key) {
+			return "", fmt.Errorf("field name %q is not a single character", key)
+		}
+		to, err := iter.Value().String()
+		if err != nil {
+			return "", err
+		}
+		table[r] = to
+	}
+
+	var b strings.Builder
+	for _, r := range s {
+		if to, ok := table[r]; ok {
+			b.WriteString(to)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String(), nil
+}
+
+// PadLeft returns s padded on the left with pad to a width of width runes. If
+// pad is empty, s is padded with spaces. A pad of multiple runes is repeated
+// and truncated as needed to fill exactly. If s is already at least width
+// runes long, it is returned unchanged. It is an error if width exceeds 1<<24.
+//
+// For instance,
+//
+//	strings.PadLeft("42", 5, "0")
+//
+// results in "00042".
+func PadLeft(s string, width int, pad string) (string, error) {
+	p, err := padding(s, width, pad)
+	return p + s, err
+}
+
+// PadRight returns s padded on the right with pad to a width of width runes.
+// See PadLeft for how pad is used.
+//
+// For instance,
+//
+//	strings.PadRight("ab", 7, "-=")
+//
+// results in "ab-=-=-".
+func PadRight(s string, width int, pad string) (string, error) {
+	p, err := padding(s, width, pad)
+	return s + p, err
+}
+
+// maxLen is the maximum width, in runes, of the strings created by PadLeft
+// and PadRight, to avoid exhausting memory on unreasonable arguments.
+const maxLen = 1 << 24
+
+// padding returns the padding needed to extend s to width runes.
+func padding(s string, width int, pad string) (string, error) {
+	if width > maxLen {
+		return "", fmt.Errorf("width %d exceeds maximum of %d", width, maxLen)
+	}
+	n := width - utf8.RuneCountInString(s)
+	if n <= 0 {
+		return "", nil
+	}
+	if pad == "" {
+		pad = " "
+	}
+	runes := []rune(pad)
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		b.WriteRune(runes[i%len(runes)])
+	}
+	return b.String(), nil
+}
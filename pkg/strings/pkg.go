@@ -53,6 +53,30 @@ var pkg = &internal.Package{
 				c.Ret = Runes(s)
 			}
 		},
+	}, {
+		Name: "RuneLen",
+		Params: []internal.Param{
+			{Kind: adt.StringKind},
+		},
+		Result: adt.IntKind,
+		Func: func(c *internal.CallCtxt) {
+			s := c.String(0)
+			if c.Do() {
+				c.Ret = RuneLen(s)
+			}
+		},
+	}, {
+		Name: "ByteLen",
+		Params: []internal.Param{
+			{Kind: adt.BytesKind | adt.StringKind},
+		},
+		Result: adt.IntKind,
+		Func: func(c *internal.CallCtxt) {
+			b := c.Bytes(0)
+			if c.Do() {
+				c.Ret = ByteLen(b)
+			}
+		},
 	}, {
 		Name: "MinRunes",
 		Params: []internal.Param{
@@ -117,6 +141,73 @@ var pkg = &internal.Package{
 				c.Ret, c.Err = SliceRunes(s, start, end)
 			}
 		},
+	}, {
+		Name: "SplitAny",
+		Params: []internal.Param{
+			{Kind: adt.StringKind},
+			{Kind: adt.StringKind},
+		},
+		Result: adt.ListKind,
+		Func: func(c *internal.CallCtxt) {
+			s, chars := c.String(0), c.String(1)
+			if c.Do() {
+				c.Ret = SplitAny(s, chars)
+			}
+		},
+	}, {
+		Name: "TrimAnyPrefix",
+		Params: []internal.Param{
+			{Kind: adt.StringKind},
+			{Kind: adt.ListKind},
+		},
+		Result: adt.StringKind,
+		Func: func(c *internal.CallCtxt) {
+			s, prefixes := c.String(0), c.StringList(1)
+			if c.Do() {
+				c.Ret = TrimAnyPrefix(s, prefixes)
+			}
+		},
+	}, {
+		Name: "Translate",
+		Params: []internal.Param{
+			{Kind: adt.StringKind},
+			{Kind: adt.TopKind},
+		},
+		Result: adt.StringKind,
+		Func: func(c *internal.CallCtxt) {
+			s, m := c.String(0), c.Value(1)
+			if c.Do() {
+				c.Ret, c.Err = Translate(s, m)
+			}
+		},
+	}, {
+		Name: "PadLeft",
+		Params: []internal.Param{
+			{Kind: adt.StringKind},
+			{Kind: adt.IntKind},
+			{Kind: adt.StringKind},
+		},
+		Result: adt.StringKind,
+		Func: func(c *internal.CallCtxt) {
+			s, width, pad := c.String(0), c.Int(1), c.String(2)
+			if c.Do() {
+				c.Ret, c.Err = PadLeft(s, width, pad)
+			}
+		},
+	}, {
+		Name: "PadRight",
+		Params: []internal.Param{
+			{Kind: adt.StringKind},
+			{Kind: adt.IntKind},
+			{Kind: adt.StringKind},
+		},
+		Result: adt.StringKind,
+		Func: func(c *internal.CallCtxt) {
+			s, width, pad := c.String(0), c.Int(1), c.String(2)
+			if c.Do() {
+				c.Ret, c.Err = PadRight(s, width, pad)
+			}
+		},
 	}, {
 		Name: "Compare",
 		Params: []internal.Param{
@@ -36,7 +36,7 @@ func MinFields(object internal.Struct, n int) (bool, error) {
 	if count < n {
 		return false, internal.ValidationError{B: &adt.Bottom{
 			Code: code,
-			Err:  errors.Newf(token.NoPos, "len(fields) < MinFields(%[2]d) (%[1]d < %[2]d)", count, n),
+			Err:  errors.Newf(token.NoPos, "struct must have at least %d fields, got %d", n, count),
 		}}
 	}
 	return true, nil
@@ -52,7 +52,7 @@ func MaxFields(object internal.Struct, n int) (bool, error) {
 	if count > n {
 		return false, internal.ValidationError{B: &adt.Bottom{
 			Code: adt.EvalError,
-			Err:  errors.Newf(token.NoPos, "len(fields) > MaxFields(%[2]d) (%[1]d > %[2]d)", count, n),
+			Err:  errors.Newf(token.NoPos, "struct must have at most %d fields, got %d", n, count),
 		}}
 	}
 
@@ -240,5 +240,31 @@ var pkg = &internal.Package{
 				c.Ret, c.Err = Split(t)
 			}
 		},
+	}, {
+		Name: "Truncate",
+		Params: []internal.Param{
+			{Kind: adt.StringKind},
+			{Kind: adt.StringKind},
+		},
+		Result: adt.StringKind,
+		Func: func(c *internal.CallCtxt) {
+			t, d := c.String(0), c.String(1)
+			if c.Do() {
+				c.Ret, c.Err = Truncate(t, d)
+			}
+		},
+	}, {
+		Name: "Round",
+		Params: []internal.Param{
+			{Kind: adt.StringKind},
+			{Kind: adt.StringKind},
+		},
+		Result: adt.StringKind,
+		Func: func(c *internal.CallCtxt) {
+			t, d := c.String(0), c.String(1)
+			if c.Do() {
+				c.Ret, c.Err = Round(t, d)
+			}
+		},
 	}},
 }
@@ -251,3 +251,44 @@ func Split(t string) (*Parts, error) {
 		Nanosecond: st.Nanosecond(),
 	}, nil
 }
+
+// Truncate returns the result of rounding the time t down to a multiple of the
+// duration d, which is a duration string such as "15m". If d <= 0, Truncate
+// returns t unchanged.
+//
+// Truncate operates on the time as an absolute duration since the zero time,
+// which is in UTC; it does not operate on the presentation form of the time.
+// The result is in the same time zone as t.
+func Truncate(t, d string) (string, error) {
+	st, dur, err := parseTimeAndDuration(t, d)
+	if err != nil {
+		return "", err
+	}
+	return st.Truncate(dur).Format(time.RFC3339Nano), nil
+}
+
+// Round returns the result of rounding the time t to the nearest multiple of
+// the duration d, which is a duration string such as "15m". Halfway values
+// round up. If d <= 0, Round returns t unchanged.
+//
+// As with Truncate, Round operates on the time as an absolute duration since
+// the zero time. The result is in the same time zone as t.
+func Round(t, d string) (string, error) {
+	st, dur, err := parseTimeAndDuration(t, d)
+	if err != nil {
+		return "", err
+	}
+	return st.Round(dur).Format(time.RFC3339Nano), nil
+}
+
+func parseTimeAndDuration(t, d string) (time.Time, time.Duration, error) {
+	st, err := time.Parse(time.RFC3339Nano, t)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	dur, err := time.ParseDuration(d)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	return st, dur, nil
+}